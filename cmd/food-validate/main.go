@@ -0,0 +1,103 @@
+// Command food-validate gera o relatório de qualidade dos alimentos TACO já importados:
+// consistência de Atwater, valores impossíveis, macronutrientes acima de 100 g e medidas
+// caseiras ausentes ou inválidas. Sai com código 1 se algum alimento tiver erro.
+//
+// Uso:
+//
+//	go run ./cmd/food-validate --endpoint http://localhost:8000
+//	go run ./cmd/food-validate --source embedded --json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"saas-nutri/internal/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func main() {
+	source := flag.String("source", "dynamodb", "Origem dos dados: dynamodb ou embedded (snapshot embutido no servidor)")
+	endpoint := flag.String("endpoint", "", "Endpoint do DynamoDB (ex: http://localhost:8000 para DynamoDB Local)")
+	region := flag.String("region", "sa-east-1", "Região AWS")
+	foodsTable := flag.String("table", "TacoFoods", "Tabela de alimentos")
+	indexName := flag.String("index", "FoodNameIndex", "GSI de busca por nome")
+	measuresTable := flag.String("measures-table", "HouseholdMeasures", "Tabela de medidas caseiras")
+	asJSON := flag.Bool("json", false, "Imprime o relatório em JSON")
+	errorsOnly := flag.Bool("errors-only", false, "Lista apenas os alimentos com erro")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	var repo client.FoodRepository
+	switch *source {
+	case "embedded":
+		embedded, err := client.NewEmbeddedFoodRepository()
+		if err != nil {
+			log.Fatalf("Erro ao carregar snapshot embutido: %v", err)
+		}
+		repo = embedded
+	case "dynamodb":
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region))
+		if err != nil {
+			log.Fatalf("Erro ao carregar configuração AWS: %v", err)
+		}
+		db := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			if *endpoint != "" {
+				o.BaseEndpoint = aws.String(*endpoint)
+			}
+		})
+		repo = client.NewTacoRepository(db, *foodsTable, *indexName, *measuresTable)
+	default:
+		log.Fatalf("Origem inválida: '%s' (use dynamodb ou embedded)", *source)
+	}
+
+	report, err := client.ValidateRepository(ctx, repo)
+	if err != nil {
+		log.Fatalf("Erro ao validar alimentos: %v", err)
+	}
+
+	if *errorsOnly {
+		filtered := report.Foods[:0]
+		for _, result := range report.Foods {
+			if client.HasValidationErrors(result.Issues) {
+				filtered = append(filtered, result)
+			}
+		}
+		report.Foods = filtered
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Erro ao gerar JSON: %v", err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if report.FoodsWithErrors > 0 {
+		os.Exit(1)
+	}
+}
+
+func printReport(report *client.ValidationReport) {
+	fmt.Println("=== Relatório de qualidade dos alimentos ===")
+	fmt.Printf("Alimentos verificados:  %d\n", report.FoodsChecked)
+	fmt.Printf("Alimentos com erro:     %d\n", report.FoodsWithErrors)
+	fmt.Printf("Alimentos com aviso:    %d\n", report.FoodsWithWarnings)
+
+	for _, result := range report.Foods {
+		fmt.Printf("\n%s  %s\n", result.FoodID, result.FoodName)
+		for _, issue := range result.Issues {
+			fmt.Printf("  [%s] %s: %s\n", issue.Severity, issue.Code, issue.Message)
+		}
+	}
+}
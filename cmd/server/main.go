@@ -1,6 +1,6 @@
 // @title           API SaaS Nutri
 // @version         1.0
-// @description     API para o MVP do SaaS para nutricionistas.
+// @description     API para o MVP do SaaS para nutricionistas. Nomes de alimentos e de medidas caseiras saem no idioma pedido pelo parâmetro lang ou pelo header Accept-Language (pt, es ou en), com português quando falta tradução.
 // @termsOfService  http://swagger.io/terms/
 
 // @contact.name   API Support
@@ -13,16 +13,28 @@
 // @host      localhost:8080
 // @BasePath  /api
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+
+// @securityDefinitions.apikey  AdminKey
+// @in                          header
+// @name                        X-Admin-Key
+
 package main
 
 import (
 	"context"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 	_ "saas-nutri/docs"
 	"saas-nutri/internal/client"
 	"saas-nutri/internal/handler"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/go-chi/chi/v5"
@@ -52,7 +64,7 @@ func main() {
     "http://localhost:4200",               
 	},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Admin-Key"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -64,23 +76,206 @@ func main() {
 
 	log.Println("Inicializando dependências...")
 
-	awsRegion := "sa-east-1"
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(awsRegion))
+	var foodRepo client.FoodRepository
+	var customFoodRepo client.CustomFoodRepository
+	var recipeRepo client.RecipeRepository
+	var tagOverrideRepo client.TagOverrideRepository
+	var synonymRepo client.SynonymRepository
+	var translationRepo client.TranslationRepository
+	useFallback := false
+	var tacoView *client.TacoView
+	switch os.Getenv("FOOD_REPOSITORY") {
+	case "embedded":
+		embeddedRepo, err := client.NewEmbeddedFoodRepository()
+		if err != nil {
+			log.Fatalf("PANIC: Erro ao carregar snapshot TACO embutido: %v", err)
+		}
+		foodRepo = embeddedRepo
+		warnIfSampleSnapshot()
+		customFoodRepo = client.NewInMemoryCustomFoodRepository()
+		recipeRepo = client.NewInMemoryRecipeRepository()
+		tagOverrideRepo = client.NewInMemoryTagOverrideRepository()
+		synonymRepo = client.NewInMemorySynonymRepository()
+		translationRepo = client.NewInMemoryTranslationRepository()
+		log.Println("Repositório TACO embutido inicializado (sem AWS; alimentos próprios e receitas em memória).")
+	case "memory":
+		foods, measures := client.SampleTacoFoods()
+		foodRepo = client.NewInMemoryFoodRepository(foods, measures)
+		customFoodRepo = client.NewInMemoryCustomFoodRepository()
+		recipeRepo = client.NewInMemoryRecipeRepository()
+		tagOverrideRepo = client.NewInMemoryTagOverrideRepository()
+		synonymRepo = client.NewInMemorySynonymRepository()
+		translationRepo = client.NewInMemoryTranslationRepository()
+		log.Println("Repositório de alimentos em memória inicializado (dados de exemplo).")
+	default:
+		awsRegion := "sa-east-1"
+		cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(awsRegion))
+		if err != nil {
+			log.Fatalf("PANIC: Erro ao carregar configuração AWS para API: %v", err)
+		}
+		dynamoClient := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			if endpoint := os.Getenv("DYNAMODB_ENDPOINT"); endpoint != "" {
+				o.BaseEndpoint = aws.String(endpoint)
+				log.Println("DynamoDB apontando para endpoint local:", endpoint)
+			}
+		})
+		log.Println("Cliente DynamoDB inicializado na região:", awsRegion)
+
+		tacoTableName := "TacoFoods"
+		tacoIndexName := "FoodNameIndex"
+		measuresTableName := "HouseholdMeasures"
+		tacoRepo := client.NewTacoRepository(dynamoClient, tacoTableName, tacoIndexName, measuresTableName)
+		foodRepo = tacoRepo
+		tacoView = tacoRepo.View
+		log.Println("Repositório TACO (DynamoDB) inicializado.")
+		useFallback = os.Getenv("FOOD_FALLBACK") == "on"
+
+		customFoodsTableName := "CustomFoods"
+		customFoodRepo = client.NewDynamoCustomFoodRepository(dynamoClient, customFoodsTableName)
+		log.Println("Repositório de alimentos próprios (DynamoDB) inicializado.")
+
+		recipesTableName := "Recipes"
+		recipeRepo = client.NewDynamoRecipeRepository(dynamoClient, recipesTableName)
+		log.Println("Repositório de receitas (DynamoDB) inicializado.")
+
+		tagOverridesTableName := "FoodTagOverrides"
+		tagOverrideRepo = client.NewDynamoTagOverrideRepository(dynamoClient, tagOverridesTableName)
+		log.Println("Repositório de correções de tags (DynamoDB) inicializado.")
+
+		synonymsTableName := "FoodSynonyms"
+		synonymRepo = client.NewDynamoSynonymRepository(dynamoClient, synonymsTableName)
+		log.Println("Repositório de sinônimos (DynamoDB) inicializado.")
+
+		translationsTableName := "FoodTranslations"
+		translationRepo = client.NewDynamoTranslationRepository(dynamoClient, translationsTableName)
+		log.Println("Repositório de traduções (DynamoDB) inicializado.")
+	}
+
+
+	var foodCache *client.CachingFoodRepository
+	if os.Getenv("FOOD_CACHE") != "off" {
+		cacheSize := client.DefaultCacheMaxEntries
+		if rawSize := os.Getenv("FOOD_CACHE_SIZE"); rawSize != "" {
+			parsed, err := strconv.Atoi(rawSize)
+			if err != nil || parsed <= 0 {
+				log.Fatalf("PANIC: FOOD_CACHE_SIZE inválido: %s", rawSize)
+			}
+			cacheSize = parsed
+		}
+		cacheTTL := client.DefaultCacheTTL
+		if rawTTL := os.Getenv("FOOD_CACHE_TTL"); rawTTL != "" {
+			parsed, err := time.ParseDuration(rawTTL)
+			if err != nil {
+				log.Fatalf("PANIC: FOOD_CACHE_TTL inválido: %v", err)
+			}
+			cacheTTL = parsed
+		}
+		foodCache = client.NewCachingFoodRepository(foodRepo, cacheSize, cacheTTL)
+		foodRepo = foodCache
+		log.Printf("Cache de alimentos ativado (%d entradas por cache, TTL %s).", cacheSize, cacheTTL)
+	}
+
+	// O índice de nutrientes lê do principal (com cache), nunca pelo wrapper de reserva.
+	// A reserva é opcional (FOOD_FALLBACK=on) e recusada com a amostra: responder com 5
+	// alimentos como se fossem a TACO inteira é pior do que devolver o erro do DynamoDB.
+	primaryFoodRepo := foodRepo
+	var embeddedFallback client.FoodRepository
+	if useFallback && client.EmbeddedTacoSnapshotIsSample() {
+		log.Println("Aviso: FOOD_FALLBACK=on ignorado porque o snapshot TACO embutido é só uma amostra.")
+		warnIfSampleSnapshot()
+		useFallback = false
+	}
+	if useFallback {
+		embeddedRepo, err := client.NewEmbeddedFoodRepository()
+		if err != nil {
+			log.Fatalf("PANIC: Erro ao carregar snapshot TACO embutido: %v", err)
+		}
+		embeddedFallback = embeddedRepo
+		foodRepo = client.NewFallbackFoodRepository(foodRepo, embeddedRepo, client.DefaultFallbackCooldown)
+		log.Println("Snapshot TACO embutido configurado como reserva em caso de falha do DynamoDB.")
+	}
+
+	cursorCodec, err := client.NewCursorCodec(os.Getenv("CURSOR_SECRET"))
 	if err != nil {
-		log.Fatalf("PANIC: Erro ao carregar configuração AWS para API: %v", err)
+		log.Fatalf("PANIC: Erro ao inicializar cursores de paginação: %v", err)
+	}
+	if os.Getenv("CURSOR_SECRET") == "" {
+		log.Println("Aviso: CURSOR_SECRET não definido, usando segredo aleatório. Os cursores de paginação deixam de valer ao reiniciar e não são aceitos por outras réplicas; defina o mesmo CURSOR_SECRET em todas as instâncias.")
+	}
+
+	offClient := client.NewOpenFoodFactsClient()
+	if offBaseURL := os.Getenv("OFF_BASE_URL"); offBaseURL != "" {
+		offClient = client.NewOpenFoodFactsClientWithBaseURL(offBaseURL)
+		log.Println("Cliente Open Food Facts apontando para:", offBaseURL)
+	}
+
+	fdcAPIKey := os.Getenv("FDC_API_KEY")
+	if fdcAPIKey == "" {
+		log.Println("Aviso: FDC_API_KEY não definida, usando DEMO_KEY da USDA (limite baixo de requisições).")
+	}
+	usdaClient := client.NewUSDAClient(fdcAPIKey)
+	if fdcBaseURL := os.Getenv("FDC_BASE_URL"); fdcBaseURL != "" {
+		usdaClient = client.NewUSDAClientWithBaseURL(fdcAPIKey, fdcBaseURL)
+		log.Println("Cliente USDA FoodData Central apontando para:", fdcBaseURL)
+	}
+
+	federatedSearcher := client.NewFederatedSearcher(
+		client.RepositorySource("taco", foodRepo, 3*time.Second),
+		client.APIClientSource("off", offClient, 8*time.Second),
+		client.APIClientSource("usda", usdaClient, 8*time.Second),
+		client.CustomFoodSource("custom", customFoodRepo, 3*time.Second),
+		client.RecipeSource("recipes", recipeRepo, 3*time.Second),
+	)
+	log.Println("Busca federada inicializada com as fontes:", federatedSearcher.SourceNames())
+
+	foodResolver := client.NewFoodResolver(foodRepo, customFoodRepo, recipeRepo)
+	foodResolver.USDA = usdaClient
+
+	foodTagger := client.NewFoodTagger(tagOverrideRepo, client.DefaultTagOverrideTTL)
+	foodResolver.Tagger = foodTagger
+
+	translator := client.NewTranslator(translationRepo, client.DefaultTranslationTTL)
+	foodResolver.Translator = translator
+	federatedSearcher.Translator = translator
+
+	// A busca do TacoRepository e o índice de nutrientes compartilham a mesma cópia da TACO.
+	if tacoView == nil {
+		tacoView = client.NewTacoView(primaryFoodRepo.ListFoods)
+	}
+	tacoView.TTL = client.DefaultTacoViewTTL
+	if rawTTL := os.Getenv("TACO_VIEW_TTL"); rawTTL != "" {
+		tacoView.TTL, err = time.ParseDuration(rawTTL)
+		if err != nil {
+			log.Fatalf("PANIC: TACO_VIEW_TTL inválido: %v", err)
+		}
 	}
-	dynamoClient := dynamodb.NewFromConfig(cfg)
-	log.Println("Cliente DynamoDB inicializado na região:", awsRegion)
+	nutrientIndex := client.NewNutrientIndex(tacoView)
+	nutrientIndex.Fallback = embeddedFallback
+	log.Println("Cópia em memória da TACO compartilhada pela busca e pelo índice de nutrientes, TTL de", tacoView.TTL)
 
+	synonymDictionary := client.NewSynonymDictionary(synonymRepo, client.DefaultSynonymTTL)
 
-	tacoTableName := "TacoFoods"
-	tacoIndexName := "FoodNameIndex"
-	tacoRepo := client.NewTacoRepository(dynamoClient, tacoTableName, tacoIndexName)
-	log.Println("Repositório TACO (DynamoDB) inicializado.")
+	foodHandler := handler.NewFoodHandler(foodRepo, foodResolver, cursorCodec, federatedSearcher, nutrientIndex, synonymDictionary)
+	log.Println("Handler de Alimentos inicializado.")
 
+	barcodeHandler := handler.NewBarcodeHandler(offClient)
+	customFoodHandler := handler.NewCustomFoodHandler(customFoodRepo)
+	recipeHandler := handler.NewRecipeHandler(foodResolver)
+	tagHandler := handler.NewTagHandler(foodTagger, foodResolver)
+	synonymHandler := handler.NewSynonymHandler(synonymDictionary)
+	translationHandler := handler.NewTranslationHandler(translator, foodResolver)
+	validationHandler := handler.NewValidationHandler(foodRepo)
 
-	foodHandler := handler.NewFoodHandler(tacoRepo)
-	log.Println("Handler de Alimentos inicializado (modo TACO only).")
+	tenantAuth, err := handler.NewTenantAuth(os.Getenv("TENANT_API_KEYS"))
+	if err != nil {
+		log.Fatalf("PANIC: Erro ao carregar TENANT_API_KEYS: %v", err)
+	}
+	log.Printf("Autenticação por tenant inicializada com %d chaves.", tenantAuth.TenantCount())
+
+	adminAuth := handler.NewAdminAuth(os.Getenv("ADMIN_API_KEY"))
+	if !adminAuth.Enabled() {
+		log.Println("Aviso: ADMIN_API_KEY não definida, rotas /api/admin desativadas.")
+	}
 
 
 	log.Println("Configurando rotas...")
@@ -92,17 +287,104 @@ func main() {
 	r.Route("/api", func(r chi.Router) {
 		log.Println("Configurando rotas sob /api...")
 
+		r.Use(tenantAuth.OptionalTenant)
+		r.Use(handler.DetectLocale)
+
 		r.Route("/foods", func(r chi.Router) {
 		r.Get("/", foodHandler.SearchFoods)
 		log.Println("Rota GET /api/foods configurada.")
 
+		r.Get("/query", foodHandler.QueryFoods)
+		log.Println("Rota GET /api/foods/query configurada.")
+
+		r.Post("/batch", foodHandler.GetFoodsBatch)
+		log.Println("Rota POST /api/foods/batch configurada.")
+
+		r.Get("/compare", foodHandler.CompareFoods)
+		log.Println("Rota GET /api/foods/compare configurada.")
+
+		r.Get("/export", foodHandler.ExportFoods)
+		log.Println("Rota GET /api/foods/export configurada.")
+
+		r.Get("/barcode/{ean}", barcodeHandler.GetFoodByBarcode)
+		log.Println("Rota GET /api/foods/barcode/{ean} configurada.")
+
 		r.Get("/{foodId}", foodHandler.GetFoodWithMeasures)
 		log.Println("Rota GET /api/foods/{foodId} configurada.")
 
 		r.Get("/{foodId}/measures", foodHandler.GetFoodMeasures)
 		log.Println("Rota GET /api/foods/{foodId}/measures configurada.")
+
+		// Medidas caseiras da TACO são compartilhadas por todos os tenants: só a curadoria edita.
+		r.Group(func(r chi.Router) {
+			r.Use(adminAuth.RequireAdmin)
+			r.Post("/{foodId}/measures", foodHandler.CreateFoodMeasure)
+			r.Put("/{foodId}/measures/{measureName}", foodHandler.UpdateFoodMeasure)
+			r.Delete("/{foodId}/measures/{measureName}", foodHandler.DeleteFoodMeasure)
+		})
+		log.Println("Rotas POST/PUT/DELETE /api/foods/{foodId}/measures configuradas (exigem chave administrativa).")
+
+		r.Post("/{foodId}/portion", foodHandler.CalculateFoodPortion)
+		log.Println("Rota POST /api/foods/{foodId}/portion configurada.")
+
+		r.Get("/{foodId}/substitutes", foodHandler.GetFoodSubstitutes)
+		log.Println("Rota GET /api/foods/{foodId}/substitutes configurada.")
 	})
 
+		r.Get("/food-groups", foodHandler.ListFoodGroups)
+		log.Println("Rota GET /api/food-groups configurada.")
+
+		r.Route("/custom-foods", func(r chi.Router) {
+			r.Use(tenantAuth.RequireTenant)
+
+			r.Post("/", customFoodHandler.CreateCustomFood)
+			r.Get("/", customFoodHandler.ListCustomFoods)
+			r.Get("/{foodId}", customFoodHandler.GetCustomFood)
+			r.Put("/{foodId}", customFoodHandler.UpdateCustomFood)
+			r.Delete("/{foodId}", customFoodHandler.DeleteCustomFood)
+			log.Println("Rotas /api/custom-foods configuradas.")
+		})
+
+		r.Route("/recipes", func(r chi.Router) {
+			r.Use(tenantAuth.RequireTenant)
+
+			r.Post("/", recipeHandler.CreateRecipe)
+			r.Get("/", recipeHandler.ListRecipes)
+			r.Get("/{recipeId}", recipeHandler.GetRecipe)
+			r.Put("/{recipeId}", recipeHandler.UpdateRecipe)
+			r.Delete("/{recipeId}", recipeHandler.DeleteRecipe)
+			log.Println("Rotas /api/recipes configuradas.")
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(adminAuth.RequireAdmin)
+
+			r.Get("/tag-overrides", tagHandler.ListTagOverrides)
+			r.Put("/tag-overrides/{foodId}", tagHandler.PutTagOverride)
+			r.Delete("/tag-overrides/{foodId}", tagHandler.DeleteTagOverride)
+			log.Println("Rotas /api/admin/tag-overrides configuradas.")
+
+			r.Get("/synonyms", synonymHandler.ListSynonyms)
+			r.Put("/synonyms/{term}", synonymHandler.PutSynonym)
+			r.Delete("/synonyms/{term}", synonymHandler.DeleteSynonym)
+			log.Println("Rotas /api/admin/synonyms configuradas.")
+
+			r.Get("/translations", translationHandler.ListTranslations)
+			r.Put("/translations/{foodId}/{locale}", translationHandler.PutTranslation)
+			r.Delete("/translations/{foodId}/{locale}", translationHandler.DeleteTranslation)
+			log.Println("Rotas /api/admin/translations configuradas.")
+
+			r.Get("/validation", validationHandler.GetValidationReport)
+			log.Println("Rota GET /api/admin/validation configurada.")
+
+			if foodCache != nil {
+				cacheHandler := handler.NewCacheHandler(foodCache)
+				r.Get("/cache", cacheHandler.GetCacheStats)
+				r.Delete("/cache", cacheHandler.PurgeCache)
+				log.Println("Rotas /api/admin/cache configuradas.")
+			}
+		})
+
 
 	})
 
@@ -114,4 +396,11 @@ func main() {
 	if err != nil {
 		log.Fatalf("PANIC: Erro fatal ao iniciar o servidor HTTP na porta %s: %v", port, err)
 	}
-}
\ No newline at end of file
+}
+// warnIfSampleSnapshot avisa quando o binário foi compilado com a amostra da TACO, e não com o
+// snapshot completo gerado pelo taco-import: a busca e a reserva cobrem só poucos alimentos.
+func warnIfSampleSnapshot() {
+	if client.EmbeddedTacoSnapshotIsSample() {
+		log.Println("Aviso: o snapshot TACO embutido é só uma amostra. Gere o completo com 'go run ./cmd/taco-import --taco taco.csv --measures medidas.csv --snapshot internal/client/taco_snapshot.json' e recompile.")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	batchWriteSize   = 25
+	maxWriteAttempts = 8
+)
+
+type tableNames struct {
+	foods    string
+	index    string
+	measures string
+}
+
+func ensureTables(ctx context.Context, db *dynamodb.Client, names tableNames) error {
+	foodsInput := &dynamodb.CreateTableInput{
+		TableName:   aws.String(names.foods),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("food_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("data_source"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("normalized_name"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("food_id"), KeyType: types.KeyTypeHash},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String(names.index),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("data_source"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("normalized_name"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}},
+	}
+
+	measuresInput := &dynamodb.CreateTableInput{
+		TableName:   aws.String(names.measures),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("food_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("measure_name"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("food_id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("measure_name"), KeyType: types.KeyTypeRange},
+		},
+	}
+
+	for _, input := range []*dynamodb.CreateTableInput{foodsInput, measuresInput} {
+		if err := createTableIfMissing(ctx, db, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createTableIfMissing(ctx context.Context, db *dynamodb.Client, input *dynamodb.CreateTableInput) error {
+	tableName := aws.ToString(input.TableName)
+
+	_, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName})
+	if err == nil {
+		log.Printf("Tabela '%s' já existe.", tableName)
+		return nil
+	}
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return fmt.Errorf("erro ao verificar tabela %s: %w", tableName, err)
+	}
+
+	log.Printf("Criando tabela '%s'...", tableName)
+	if _, err := db.CreateTable(ctx, input); err != nil {
+		return fmt.Errorf("erro ao criar tabela %s: %w", tableName, err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName}, 5*time.Minute); err != nil {
+		return fmt.Errorf("erro aguardando criação da tabela %s: %w", tableName, err)
+	}
+	log.Printf("Tabela '%s' criada.", tableName)
+	return nil
+}
+
+// writeAll grava os itens com BatchWriteItem em lotes de 25. PutRequest sobrescreve o item
+// com a mesma chave, então repetir a importação é idempotente.
+func writeAll[T any](ctx context.Context, db *dynamodb.Client, tableName string, items []T) error {
+	for start := 0; start < len(items); start += batchWriteSize {
+		end := min(start+batchWriteSize, len(items))
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, item := range items[start:end] {
+			av, err := attributevalue.MarshalMap(item)
+			if err != nil {
+				return fmt.Errorf("erro ao serializar item para %s: %w", tableName, err)
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+		}
+
+		if err := writeBatch(ctx, db, tableName, requests); err != nil {
+			return err
+		}
+		log.Printf("Tabela '%s': %d/%d itens gravados.", tableName, end, len(items))
+	}
+	return nil
+}
+
+func writeBatch(ctx context.Context, db *dynamodb.Client, tableName string, requests []types.WriteRequest) error {
+	pending := map[string][]types.WriteRequest{tableName: requests}
+	backoff := 100 * time.Millisecond
+
+	for attempt := 1; attempt <= maxWriteAttempts; attempt++ {
+		out, err := db.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+		if err != nil {
+			return fmt.Errorf("erro no BatchWriteItem da tabela %s: %w", tableName, err)
+		}
+		if len(out.UnprocessedItems[tableName]) == 0 {
+			return nil
+		}
+
+		pending = out.UnprocessedItems
+		log.Printf("Tabela '%s': %d itens não processados, nova tentativa em %s (tentativa %d/%d).",
+			tableName, len(pending[tableName]), backoff, attempt, maxWriteAttempts)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("tabela %s: %d itens continuaram não processados após %d tentativas", tableName, len(pending[tableName]), maxWriteAttempts)
+}
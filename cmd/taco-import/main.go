@@ -0,0 +1,196 @@
+// Command taco-import cria e popula as tabelas TacoFoods e HouseholdMeasures a partir
+// da planilha TACO exportada em CSV.
+//
+// Uso:
+//
+//	go run ./cmd/taco-import --taco taco.csv --measures medidas.csv --dry-run
+//	go run ./cmd/taco-import --taco taco.csv --endpoint http://localhost:8000 --create-tables
+//	go run ./cmd/taco-import --taco taco.csv --measures medidas.csv --snapshot internal/client/taco_snapshot.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"saas-nutri/internal/client"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func main() {
+	tacoPath := flag.String("taco", "", "CSV da tabela TACO (obrigatório)")
+	measuresPath := flag.String("measures", "", "CSV de medidas caseiras (food_id, measure_name, measure_quantity, measure_weight_g)")
+	delimiter := flag.String("delimiter", ";", "Separador de colunas dos CSVs")
+	dryRun := flag.Bool("dry-run", false, "Apenas valida os arquivos e imprime o relatório, sem gravar")
+	endpoint := flag.String("endpoint", "", "Endpoint do DynamoDB (ex: http://localhost:8000 para DynamoDB Local)")
+	region := flag.String("region", "sa-east-1", "Região AWS")
+	createTables := flag.Bool("create-tables", false, "Cria as tabelas e o GSI caso não existam")
+	foodsTable := flag.String("table", "TacoFoods", "Tabela de alimentos")
+	indexName := flag.String("index", "FoodNameIndex", "GSI de busca por nome")
+	measuresTable := flag.String("measures-table", "HouseholdMeasures", "Tabela de medidas caseiras")
+	snapshotPath := flag.String("snapshot", "", "Grava o snapshot JSON embutido no servidor neste arquivo, sem acessar o DynamoDB")
+	flag.Parse()
+
+	if *tacoPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	sep, size := utf8.DecodeRuneInString(*delimiter)
+	if size == 0 || size != len(*delimiter) {
+		log.Fatalf("Separador inválido: '%s'", *delimiter)
+	}
+
+	report := newParseReport()
+
+	tacoFile, err := os.Open(*tacoPath)
+	if err != nil {
+		log.Fatalf("Erro ao abrir CSV TACO: %v", err)
+	}
+	foods, err := parseTacoFoods(tacoFile, sep, report)
+	tacoFile.Close()
+	if err != nil {
+		log.Fatalf("Erro ao processar CSV TACO: %v", err)
+	}
+
+	var measures []client.MeasureItem
+	if *measuresPath != "" {
+		knownFoods := make(map[string]bool, len(foods))
+		for _, f := range foods {
+			knownFoods[f.FoodID] = true
+		}
+		measuresFile, err := os.Open(*measuresPath)
+		if err != nil {
+			log.Fatalf("Erro ao abrir CSV de medidas: %v", err)
+		}
+		measures, err = parseMeasures(measuresFile, sep, knownFoods, report)
+		measuresFile.Close()
+		if err != nil {
+			log.Fatalf("Erro ao processar CSV de medidas: %v", err)
+		}
+	}
+
+	foods, measures = applyValidation(foods, measures, *measuresPath != "", report)
+
+	printReport(report, foods, measures)
+
+	if *dryRun {
+		if len(report.Errors) > 0 || len(report.Rejected) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *snapshotPath != "" {
+		if err := writeSnapshot(*snapshotPath, foods, measures); err != nil {
+			log.Fatalf("Erro ao gravar snapshot: %v", err)
+		}
+		log.Printf("Snapshot gravado em %s: %d alimentos e %d medidas.", *snapshotPath, len(foods), len(measures))
+		return
+	}
+
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region))
+	if err != nil {
+		log.Fatalf("Erro ao carregar configuração AWS: %v", err)
+	}
+	db := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if *endpoint != "" {
+			o.BaseEndpoint = aws.String(*endpoint)
+		}
+	})
+
+	names := tableNames{foods: *foodsTable, index: *indexName, measures: *measuresTable}
+	if *createTables {
+		if err := ensureTables(ctx, db, names); err != nil {
+			log.Fatalf("Erro ao preparar tabelas: %v", err)
+		}
+	}
+
+	if err := writeAll(ctx, db, names.foods, foods); err != nil {
+		log.Fatalf("Erro ao gravar alimentos: %v", err)
+	}
+	if err := writeAll(ctx, db, names.measures, measures); err != nil {
+		log.Fatalf("Erro ao gravar medidas: %v", err)
+	}
+
+	log.Printf("Importação concluída: %d alimentos e %d medidas.", len(foods), len(measures))
+}
+
+func writeSnapshot(path string, foods []client.TacoFoodItem, measures []client.MeasureItem) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := client.NewTacoSnapshot(foods, measures).Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func printReport(report *parseReport, foods []client.TacoFoodItem, measures []client.MeasureItem) {
+	fmt.Println("=== Relatório de validação TACO ===")
+	fmt.Printf("Linhas de alimentos lidas: %d\n", report.Rows)
+	fmt.Printf("Alimentos válidos:         %d\n", len(foods))
+	fmt.Printf("Alimentos sem grupo:       %d\n", report.MissingGroup)
+	fmt.Printf("Medidas caseiras válidas:  %d\n", len(measures))
+	fmt.Printf("Erros:                     %d\n", len(report.Errors))
+	fmt.Printf("Erros de validação:        %d\n", len(report.Rejected))
+	fmt.Printf("Avisos de qualidade:       %d\n", len(report.QualityWarnings))
+
+	if len(report.Trace) > 0 || len(report.NotAnalyzed) > 0 {
+		fmt.Println("\nNutrientes com traço (Tr) / não analisados (NA):")
+		keys := make(map[string]bool)
+		for k := range report.Trace {
+			keys[k] = true
+		}
+		for k := range report.NotAnalyzed {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			fmt.Printf("  %-22s Tr=%-4d NA=%d\n", k, report.Trace[k], report.NotAnalyzed[k])
+		}
+	}
+
+	if len(report.DuplicateNames) > 0 {
+		fmt.Println("\nNomes normalizados duplicados:")
+		for _, d := range report.DuplicateNames {
+			fmt.Println("  " + d)
+		}
+	}
+	if len(report.UnknownFoods) > 0 {
+		fmt.Println("\nMedidas descartadas por referenciarem alimentos fora do CSV TACO:")
+		for _, id := range report.UnknownFoods {
+			fmt.Println("  " + id)
+		}
+	}
+	if len(report.Rejected) > 0 {
+		fmt.Println("\nAlimentos rejeitados pela validação (não serão importados):")
+		for _, r := range report.Rejected {
+			fmt.Println("  " + r)
+		}
+	}
+	if len(report.QualityWarnings) > 0 {
+		fmt.Println("\nAvisos de qualidade:")
+		for _, w := range report.QualityWarnings {
+			fmt.Println("  " + w)
+		}
+	}
+	if len(report.Errors) > 0 {
+		fmt.Println("\nErros por linha:")
+		for _, e := range report.Errors {
+			fmt.Printf("  %s, linha %d: %s\n", e.File, e.Line, e.Message)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+	"strconv"
+	"strings"
+)
+
+const (
+	colNumber = "number"
+	colName   = "name"
+	colEnergy = "energy_kcal"
+	colProt   = "protein_g"
+	colFat    = "fat_g"
+	colCarb   = "carbohydrate_g"
+	colFiber  = "fiber_g"
+	colGroup  = "food_group"
+)
+
+// tacoHeaders relaciona o início do cabeçalho normalizado da planilha TACO à coluna de destino.
+// A ordem importa: "energia kcal" precisa vir antes de qualquer outro prefixo "energia".
+var tacoHeaders = []struct {
+	prefix string
+	column string
+}{
+	{"numero", colNumber},
+	{"grupo", colGroup},
+	{"categoria", colGroup},
+	{"descricao", colName},
+	{"energia kcal", colEnergy},
+	{"proteina", colProt},
+	{"lipideos", colFat},
+	{"carboidrato", colCarb},
+	{"fibra", colFiber},
+	{"colesterol", model.NutrientCholesterol},
+	{"calcio", model.NutrientCalcium},
+	{"magnesio", model.NutrientMagnesium},
+	{"fosforo", model.NutrientPhosphorus},
+	{"ferro", model.NutrientIron},
+	{"sodio", model.NutrientSodium},
+	{"potassio", model.NutrientPotassium},
+	{"zinco", model.NutrientZinc},
+	{"rae", model.NutrientVitaminA},
+	{"tiamina", model.NutrientVitaminB1},
+	{"riboflavina", model.NutrientVitaminB2},
+	{"piridoxina", model.NutrientVitaminB6},
+	{"niacina", model.NutrientVitaminB3},
+	{"vitamina c", model.NutrientVitaminC},
+	{"saturados", model.NutrientSaturatedFat},
+	{"monoinsaturados", model.NutrientMonounsaturatedFat},
+	{"poliinsaturados", model.NutrientPolyunsaturatedFat},
+	{"poli insaturados", model.NutrientPolyunsaturatedFat},
+}
+
+type rowError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+type parseReport struct {
+	Rows           int
+	Errors         []rowError
+	Trace          map[string]int
+	NotAnalyzed    map[string]int
+	MissingGroup   int
+	DuplicateNames []string
+	UnknownFoods   []string
+	// Rejected e QualityWarnings vêm do motor de validação (client.ValidateFoods).
+	Rejected        []string
+	QualityWarnings []string
+}
+
+func newParseReport() *parseReport {
+	return &parseReport{Trace: make(map[string]int), NotAnalyzed: make(map[string]int)}
+}
+
+func (r *parseReport) addError(file string, line int, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, rowError{File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func newCSVReader(in io.Reader, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(in)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader
+}
+
+func mapHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, h := range header {
+		normalized := client.NormalizeFoodName(strings.TrimPrefix(h, "\ufeff"))
+		for _, known := range tacoHeaders {
+			if strings.HasPrefix(normalized, known.prefix) {
+				if _, exists := columns[known.column]; !exists {
+					columns[known.column] = i
+				}
+				break
+			}
+		}
+	}
+
+	for _, required := range []string{colNumber, colName, colEnergy, colProt, colFat, colCarb} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("coluna obrigatória não encontrada no cabeçalho: %s", required)
+		}
+	}
+	return columns, nil
+}
+
+func cell(record []string, columns map[string]int, column string) (string, bool) {
+	i, ok := columns[column]
+	if !ok || i >= len(record) {
+		return "", false
+	}
+	return strings.TrimSpace(record[i]), true
+}
+
+// findFoodGroup reconhece um grupo da TACO pelo slug ou pelo nome, ignorando acentos.
+func findFoodGroup(value string) (string, bool) {
+	normalized := client.NormalizeFoodName(value)
+	if normalized == "" {
+		return "", false
+	}
+	for _, g := range model.FoodGroups {
+		if normalized == g.Slug || normalized == client.NormalizeFoodName(g.Name) {
+			return g.Slug, true
+		}
+	}
+	return "", false
+}
+
+// parseTacoFoods lê a planilha TACO exportada em CSV. Na planilha oficial, o grupo aparece
+// como uma linha de título antes dos alimentos; também é aceita uma coluna "grupo".
+// Demais linhas sem número de alimento (notas de rodapé) são ignoradas.
+func parseTacoFoods(in io.Reader, delimiter rune, report *parseReport) ([]client.TacoFoodItem, error) {
+	reader := newCSVReader(in, delimiter)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho do CSV: %w", err)
+	}
+	columns, err := mapHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var foods []client.TacoFoodItem
+	seenNames := make(map[string]string)
+	currentGroup := ""
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			report.addError("taco", line, "linha CSV inválida: %v", err)
+			continue
+		}
+
+		number, _ := cell(record, columns, colNumber)
+		if _, err := strconv.Atoi(number); err != nil {
+			if len(record) > 0 {
+				if slug, ok := findFoodGroup(record[0]); ok {
+					currentGroup = slug
+				}
+			}
+			continue
+		}
+		report.Rows++
+
+		group := currentGroup
+		if rawGroup, ok := cell(record, columns, colGroup); ok && rawGroup != "" {
+			slug, found := findFoodGroup(rawGroup)
+			if !found {
+				report.addError("taco", line, "alimento %s com grupo desconhecido: '%s'", number, rawGroup)
+				continue
+			}
+			group = slug
+		}
+		if group == "" {
+			report.MissingGroup++
+		}
+
+		name, _ := cell(record, columns, colName)
+		if name == "" {
+			report.addError("taco", line, "alimento %s sem descrição", number)
+			continue
+		}
+
+		item := client.TacoFoodItem{
+			FoodID:         "taco-" + number,
+			DataSource:     "TACO",
+			OriginalName:   name,
+			NormalizedName: client.NormalizeFoodName(name),
+			FoodGroup:      group,
+			Nutrients:      make(map[string]client.TacoNutrient),
+		}
+
+		valid := true
+		for _, def := range []struct {
+			column string
+			target *float64
+		}{
+			{colEnergy, &item.EnergyKcal},
+			{colProt, &item.ProteinG},
+			{colFat, &item.FatG},
+			{colCarb, &item.CarbohydrateG},
+			{colFiber, &item.FiberG},
+		} {
+			raw, ok := cell(record, columns, def.column)
+			if !ok {
+				continue
+			}
+			value, err := client.ParseTacoValue(raw)
+			if err != nil {
+				report.addError("taco", line, "alimento %s, coluna %s: %v", number, def.column, err)
+				valid = false
+				continue
+			}
+			*def.target = value.Float()
+		}
+
+		for _, nd := range model.NutrientDefinitions {
+			raw, ok := cell(record, columns, nd.Key)
+			if !ok {
+				continue
+			}
+			value, err := client.ParseTacoValue(raw)
+			if err != nil {
+				report.addError("taco", line, "alimento %s, coluna %s: %v", number, nd.Key, err)
+				valid = false
+				continue
+			}
+			switch value.Status {
+			case model.NutrientStatusTrace:
+				report.Trace[nd.Key]++
+			case model.NutrientStatusNotAnalyzed:
+				report.NotAnalyzed[nd.Key]++
+			}
+			item.Nutrients[nd.Key] = value
+		}
+		if !valid {
+			continue
+		}
+
+		if previous, dup := seenNames[item.NormalizedName]; dup {
+			report.DuplicateNames = append(report.DuplicateNames, fmt.Sprintf("%s (%s e %s)", name, previous, item.FoodID))
+		}
+		seenNames[item.NormalizedName] = item.FoodID
+
+		foods = append(foods, item)
+	}
+
+	return foods, nil
+}
+
+// parseMeasures lê o CSV de medidas caseiras com as colunas
+// food_id, measure_name, measure_quantity, measure_weight_g.
+func parseMeasures(in io.Reader, delimiter rune, knownFoods map[string]bool, report *parseReport) ([]client.MeasureItem, error) {
+	reader := newCSVReader(in, delimiter)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho do CSV de medidas: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, h := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+	for _, required := range []string{"food_id", "measure_name", "measure_weight_g"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("coluna obrigatória não encontrada no CSV de medidas: %s", required)
+		}
+	}
+
+	var measures []client.MeasureItem
+	seen := make(map[string]bool)
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			report.addError("medidas", line, "linha CSV de medidas inválida: %v", err)
+			continue
+		}
+
+		foodID, _ := cell(record, columns, "food_id")
+		name, _ := cell(record, columns, "measure_name")
+		quantity, _ := cell(record, columns, "measure_quantity")
+		rawWeight, _ := cell(record, columns, "measure_weight_g")
+
+		if foodID == "" || name == "" {
+			report.addError("medidas", line, "medida sem food_id ou measure_name")
+			continue
+		}
+		weight, err := client.ParseTacoValue(rawWeight)
+		if err != nil || weight.Value == nil || *weight.Value <= 0 {
+			report.addError("medidas", line, "medida '%s' de %s com peso inválido: '%s'", name, foodID, rawWeight)
+			continue
+		}
+		if knownFoods != nil && !knownFoods[foodID] {
+			// Sem o alimento, a medida ficaria órfã na tabela: é descartada e só aparece no relatório.
+			report.UnknownFoods = append(report.UnknownFoods, foodID)
+			continue
+		}
+		measure := client.MeasureItem{
+			FoodID:          foodID,
+			MeasureName:     name,
+			MeasureQuantity: quantity,
+			GramEquivalent:  *weight.Value,
+		}
+		if err := client.ValidateMeasure(measure); err != nil {
+			report.addError("medidas", line, "medida '%s' de %s: %v", name, foodID, err)
+			continue
+		}
+		key := foodID + "|" + name
+		if seen[key] {
+			report.addError("medidas", line, "medida '%s' duplicada para %s", name, foodID)
+			continue
+		}
+		seen[key] = true
+
+		measures = append(measures, measure)
+	}
+
+	return measures, nil
+}
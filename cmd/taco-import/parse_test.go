@@ -0,0 +1,160 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+// tacoFixture imita a planilha oficial exportada em CSV: linhas de título de grupo antes dos
+// alimentos, BOM, decimais com vírgula, "Tr"/"NA" e uma nota de rodapé no fim.
+const tacoFixture = "\ufeffNúmero do Alimento;Descrição dos alimentos;Energia (kcal);Energia (kJ);Proteína (g);Lipídeos (g);Carboidrato (g);Fibra Alimentar (g);Sódio (mg);Ferro (mg)\n" +
+	"Cereais e derivados;;;;;;;;;\n" +
+	"1;Arroz, integral, cozido;124;517;2,6;1,0;25,8;2,7;1,2;0,3\n" +
+	"3;Arroz, tipo 1, cozido;128;537;2,5;0,2;28,1;1,6;1;Tr\n" +
+	"Frutas e derivados;;;;;;;;;\n" +
+	"182;Maçã, Fuji, com casca, crua;56;234;0,3;0,0;15,2;1,3;NA;0,1\n" +
+	"183;Maçã, Argentina, com casca, crua;63;263;0,2;1.234;16,6;2,0;Tr;0,1\n" +
+	"184;;50;209;0,1;0,1;12,0;1,0;1;0,1\n" +
+	"185;Arroz tipo 1 cozido;128;537;2,5;0,2;28,1;1,6;1;Tr\n" +
+	"Fonte: TACO 4ª edição;;;;;;;;;\n"
+
+func TestParseTacoFoods(t *testing.T) {
+	report := newParseReport()
+	foods, err := parseTacoFoods(strings.NewReader(tacoFixture), ';', report)
+	if err != nil {
+		t.Fatalf("parseTacoFoods: %v", err)
+	}
+
+	byID := make(map[string]int)
+	for i, f := range foods {
+		byID[f.FoodID] = i
+	}
+	if want := []string{"taco-1", "taco-3", "taco-182", "taco-185"}; len(foods) != len(want) {
+		t.Fatalf("esperava %v, veio %+v", want, foods)
+	}
+
+	rice := foods[byID["taco-3"]]
+	if rice.OriginalName != "Arroz, tipo 1, cozido" || rice.NormalizedName != "arroz tipo 1 cozido" || rice.DataSource != "TACO" {
+		t.Errorf("identificação do arroz inesperada: %+v", rice)
+	}
+	// A coluna em kJ não pode tomar o lugar da energia em kcal.
+	if rice.EnergyKcal != 128 || rice.ProteinG != 2.5 || rice.FatG != 0.2 || rice.CarbohydrateG != 28.1 || rice.FiberG != 1.6 {
+		t.Errorf("macronutrientes do arroz inesperados: %+v", rice)
+	}
+	if iron := rice.Nutrients[model.NutrientIron]; iron.Status != model.NutrientStatusTrace || iron.Value != nil {
+		t.Errorf("ferro em traço = %+v", iron)
+	}
+	if sodium := rice.Nutrients[model.NutrientSodium]; sodium.Float() != 1 || sodium.Status != model.NutrientStatusMeasured {
+		t.Errorf("sódio do arroz = %+v", sodium)
+	}
+
+	// O grupo vem da última linha de título.
+	groups := map[string]string{"taco-1": "cereais", "taco-3": "cereais", "taco-182": "frutas", "taco-185": "frutas"}
+	for id, want := range groups {
+		if got := foods[byID[id]].FoodGroup; got != want {
+			t.Errorf("%s no grupo %q; esperava %q", id, got, want)
+		}
+	}
+	if sodium := foods[byID["taco-182"]].Nutrients[model.NutrientSodium]; sodium.Status != model.NutrientStatusNotAnalyzed {
+		t.Errorf("sódio NA = %+v", sodium)
+	}
+
+	// Linhas de título e a nota de rodapé não contam como alimento.
+	if report.Rows != 6 {
+		t.Errorf("esperava 6 linhas de alimento, veio %d", report.Rows)
+	}
+	if len(report.Errors) != 2 || report.Errors[0].Line != 7 || report.Errors[1].Line != 8 {
+		t.Errorf("esperava erros no valor ambíguo (linha 7) e na descrição vazia (linha 8): %+v", report.Errors)
+	}
+	if report.Trace[model.NutrientIron] != 2 || report.NotAnalyzed[model.NutrientSodium] != 1 {
+		t.Errorf("contagem de traço/NA inesperada: %v / %v", report.Trace, report.NotAnalyzed)
+	}
+	if len(report.DuplicateNames) != 1 || !strings.Contains(report.DuplicateNames[0], "taco-3 e taco-185") {
+		t.Errorf("esperava o nome duplicado entre taco-3 e taco-185: %v", report.DuplicateNames)
+	}
+	if report.MissingGroup != 0 {
+		t.Errorf("nenhum alimento deveria ficar sem grupo: %d", report.MissingGroup)
+	}
+}
+
+func TestParseTacoFoodsGroupColumn(t *testing.T) {
+	fixture := "Número,Descrição,Grupo,Energia kcal,Proteína,Lipídeos,Carboidrato\n" +
+		"561,Feijão carioca cozido,Leguminosas e derivados,76,\"4,8\",\"0,5\",\"13,6\"\n" +
+		"562,Feijão fradinho cozido,feijoes,78,\"5,1\",\"0,6\",\"13,5\"\n" +
+		"563,Feijão jalo cozido,,93,\"6,1\",\"0,5\",\"16,5\"\n"
+	report := newParseReport()
+	foods, err := parseTacoFoods(strings.NewReader(fixture), ',', report)
+	if err != nil {
+		t.Fatalf("parseTacoFoods: %v", err)
+	}
+	if len(foods) != 2 || foods[0].FoodGroup != "leguminosas" || foods[0].ProteinG != 4.8 || foods[1].FoodGroup != "" {
+		t.Errorf("alimentos inesperados: %+v", foods)
+	}
+	if len(report.Errors) != 1 || !strings.Contains(report.Errors[0].Message, "grupo desconhecido") {
+		t.Errorf("esperava erro de grupo desconhecido: %+v", report.Errors)
+	}
+	if report.MissingGroup != 1 {
+		t.Errorf("esperava 1 alimento sem grupo, veio %d", report.MissingGroup)
+	}
+}
+
+func TestParseTacoFoodsMissingColumn(t *testing.T) {
+	fixture := "Número;Descrição;Energia (kcal);Proteína (g);Carboidrato (g)\n1;Arroz;124;2,6;25,8\n"
+	if _, err := parseTacoFoods(strings.NewReader(fixture), ';', newParseReport()); err == nil || !strings.Contains(err.Error(), colFat) {
+		t.Errorf("esperava erro de coluna %s ausente, veio %v", colFat, err)
+	}
+}
+
+func TestParseMeasures(t *testing.T) {
+	fixture := "food_id;measure_name;measure_quantity;measure_weight_g\n" +
+		"taco-3;colher de sopa cheia;1;25\n" +
+		"taco-182;unidade média;1;130,5\n" +
+		"taco-9999;xícara;1;160\n" +
+		"taco-3;colher de sopa cheia;1;25\n" +
+		"taco-1;colher de servir;1;0\n" +
+		"taco-1;;1;20\n" +
+		"taco-1;concha;1;1.234\n" +
+		"taco-8888;prato;1;200\n"
+	known := map[string]bool{"taco-1": true, "taco-3": true, "taco-182": true}
+
+	report := newParseReport()
+	measures, err := parseMeasures(strings.NewReader(fixture), ';', known, report)
+	if err != nil {
+		t.Fatalf("parseMeasures: %v", err)
+	}
+	if len(measures) != 2 || measures[0].FoodID != "taco-3" || measures[0].GramEquivalent != 25 || measures[1].GramEquivalent != 130.5 {
+		t.Errorf("medidas inesperadas: %+v", measures)
+	}
+	// Medidas de alimentos que não vieram na importação são descartadas e vão para o relatório.
+	if want := []string{"taco-9999", "taco-8888"}; !reflect.DeepEqual(report.UnknownFoods, want) {
+		t.Errorf("UnknownFoods = %v; esperava %v", report.UnknownFoods, want)
+	}
+	lines := make([]int, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		lines = append(lines, e.Line)
+	}
+	// Duplicada (5), peso zero (6), sem nome (7) e peso ambíguo (8).
+	if want := []int{5, 6, 7, 8}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("erros nas linhas %v; esperava %v: %+v", lines, want, report.Errors)
+	}
+
+	// Sem a lista de alimentos conhecidos, nenhuma medida é descartada como órfã.
+	report = newParseReport()
+	measures, err = parseMeasures(strings.NewReader(fixture), ';', nil, report)
+	if err != nil {
+		t.Fatalf("parseMeasures: %v", err)
+	}
+	if len(measures) != 4 || len(report.UnknownFoods) != 0 {
+		t.Errorf("sem alimentos conhecidos: %d medidas, órfãs %v", len(measures), report.UnknownFoods)
+	}
+}
+
+func TestParseMeasuresMissingColumn(t *testing.T) {
+	fixture := "food_id;measure_name;measure_quantity\ntaco-3;colher;1\n"
+	if _, err := parseMeasures(strings.NewReader(fixture), ';', nil, newParseReport()); err == nil || !strings.Contains(err.Error(), "measure_weight_g") {
+		t.Errorf("esperava erro de coluna measure_weight_g ausente, veio %v", err)
+	}
+}
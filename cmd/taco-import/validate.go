@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"saas-nutri/internal/client"
+)
+
+// applyValidation roda o motor de validação sobre os dados lidos. Alimentos com erro ficam
+// fora da importação, junto com suas medidas; avisos só entram no relatório. Sem CSV de
+// medidas, a regra de medidas ausentes não se aplica.
+func applyValidation(foods []client.TacoFoodItem, measures []client.MeasureItem, checkMeasures bool, report *parseReport) ([]client.TacoFoodItem, []client.MeasureItem) {
+	var byFood map[string][]client.MeasureItem
+	if checkMeasures {
+		byFood = make(map[string][]client.MeasureItem)
+		for _, m := range measures {
+			byFood[m.FoodID] = append(byFood[m.FoodID], m)
+		}
+	}
+
+	validation := client.ValidateFoods(foods, byFood)
+	rejected := make(map[string]bool)
+	for _, result := range validation.Foods {
+		blocked := client.HasValidationErrors(result.Issues)
+		if blocked {
+			rejected[result.FoodID] = true
+		}
+		for _, issue := range result.Issues {
+			line := fmt.Sprintf("%s (%s): %s", result.FoodID, result.FoodName, issue.Message)
+			if issue.Severity == client.ValidationSeverityError {
+				report.Rejected = append(report.Rejected, line)
+			} else {
+				report.QualityWarnings = append(report.QualityWarnings, line)
+			}
+		}
+	}
+	if len(rejected) == 0 {
+		return foods, measures
+	}
+
+	keptFoods := make([]client.TacoFoodItem, 0, len(foods)-len(rejected))
+	for _, f := range foods {
+		if !rejected[f.FoodID] {
+			keptFoods = append(keptFoods, f)
+		}
+	}
+	keptMeasures := make([]client.MeasureItem, 0, len(measures))
+	for _, m := range measures {
+		if !rejected[m.FoodID] {
+			keptMeasures = append(keptMeasures, m)
+		}
+	}
+	return keptFoods, keptMeasures
+}
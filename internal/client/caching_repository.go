@@ -0,0 +1,292 @@
+package client
+
+import (
+	"context"
+	"saas-nutri/internal/model"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultCacheMaxEntries = 2000
+	DefaultCacheTTL        = time.Hour
+
+	// cacheLoadTimeout limita a carga compartilhada, que não herda o cancelamento de quem a iniciou.
+	cacheLoadTimeout = 10 * time.Second
+)
+
+// CachingFoodRepository decora um FoodRepository com caches LRU com TTL para alimentos,
+// medidas e buscas por nome. Cargas simultâneas da mesma chave viram uma única chamada ao
+// repositório. Edições de medidas invalidam as entradas do alimento nesta instância; as
+// demais instâncias enxergam a edição quando o TTL expira.
+//
+// Buscas com filtros de alérgeno ou dieta não passam pelo cache, pois dependem das
+// correções manuais de tags, que mudam sem passar por este repositório.
+type CachingFoodRepository struct {
+	inner    FoodRepository
+	foods    *lruCache[*model.Food]
+	measures *lruCache[[]MeasureItem]
+	searches *lruCache[*SearchPage]
+	group    singleflight.Group
+
+	// Gerações por alimento (Invalidate) e global (Purge). Uma carga só grava no cache se a
+	// geração não mudou desde que começou; senão gravaria o dado anterior à edição.
+	genMu       sync.Mutex
+	generations map[string]uint64
+	purges      uint64
+}
+
+func NewCachingFoodRepository(inner FoodRepository, maxEntries int, ttl time.Duration) *CachingFoodRepository {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachingFoodRepository{
+		inner:       inner,
+		foods:       newLRUCache[*model.Food]("foods", maxEntries, ttl),
+		measures:    newLRUCache[[]MeasureItem]("measures", maxEntries, ttl),
+		searches:    newLRUCache[*SearchPage]("searches", maxEntries, ttl),
+		generations: make(map[string]uint64),
+	}
+}
+
+// generation devolve a geração atual das entradas do alimento; com foodID vazio, só a global.
+func (c *CachingFoodRepository) generation(foodID string) uint64 {
+	c.genMu.Lock()
+	defer c.genMu.Unlock()
+	return c.purges + c.generations[foodID]
+}
+
+// storeIfCurrent grava com store só se a geração ainda for gen. A verificação e a gravação
+// ficam sob o mesmo lock que Invalidate e Purge usam, para que nenhuma invalidação caia entre
+// as duas.
+func (c *CachingFoodRepository) storeIfCurrent(foodID string, gen uint64, store func()) {
+	c.genMu.Lock()
+	defer c.genMu.Unlock()
+	if c.purges+c.generations[foodID] == gen {
+		store()
+	}
+}
+
+// load executa fn uma única vez por chave entre chamadas simultâneas. Cada chamador ainda
+// respeita o próprio contexto enquanto espera.
+func (c *CachingFoodRepository) load(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheLoadTimeout)
+		defer cancel()
+		return fn(loadCtx)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		return res.Val, res.Err
+	}
+}
+
+// cloneFood copia o alimento para que quem recebe possa alterá-lo (ex: aplicar tags)
+// sem mexer na entrada do cache.
+func cloneFood(food *model.Food) *model.Food {
+	clone := *food
+	clone.HouseholdMeasures = append([]model.HouseholdMeasure(nil), food.HouseholdMeasures...)
+	if food.Nutrients != nil {
+		clone.Nutrients = make(map[string]model.NutrientValue, len(food.Nutrients))
+		for key, value := range food.Nutrients {
+			if value.Value != nil {
+				v := *value.Value
+				value.Value = &v
+			}
+			clone.Nutrients[key] = value
+		}
+	}
+	if food.Tags != nil {
+		tags := *food.Tags
+		// [:0:0] mantém a diferença entre lista vazia e nula no JSON.
+		tags.Allergens = append(food.Tags.Allergens[:0:0], food.Tags.Allergens...)
+		tags.Diets = append(food.Tags.Diets[:0:0], food.Tags.Diets...)
+		clone.Tags = &tags
+	}
+	return &clone
+}
+
+// loadKey separa no singleflight as cargas de gerações diferentes: quem chega depois de uma
+// invalidação não reaproveita a carga iniciada antes dela.
+func loadKey(key string, gen uint64) string {
+	return key + "#" + strconv.FormatUint(gen, 10)
+}
+
+func searchCacheKey(namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) string {
+	start := ""
+	if id, ok := exclusiveStartKey["food_id"].(*types.AttributeValueMemberS); ok {
+		start = id.Value
+	}
+	query := normalizeString(namePrefix)
+	// Os sinônimos aplicáveis entram na chave para que um sinônimo novo não espere o TTL.
+	var synonyms []string
+	for _, v := range expandQuery(query, filter.Synonyms) {
+		synonyms = append(synonyms, v.synonym)
+	}
+	// Idem para as traduções: a versão muda quando um nome do idioma muda.
+	translations := ""
+	if filter.Translations != nil {
+		translations = filter.Translations.Locale + ":" + filter.Translations.Version
+	}
+	return strings.Join([]string{query, filter.FoodGroup, strconv.Itoa(limit), start, strings.Join(synonyms, ","), translations}, "|")
+}
+
+func (c *CachingFoodRepository) SearchFoodsByNamePrefix(ctx context.Context, namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	if filter.filtersTags() {
+		return c.inner.SearchFoodsByNamePrefix(ctx, namePrefix, filter, limit, exclusiveStartKey)
+	}
+
+	key := searchCacheKey(namePrefix, filter, limit, exclusiveStartKey)
+	if page, ok := c.searches.Get(key); ok {
+		return page, nil
+	}
+	gen := c.generation("")
+	val, err := c.load(ctx, loadKey("search:"+key, gen), func(ctx context.Context) (interface{}, error) {
+		page, err := c.inner.SearchFoodsByNamePrefix(ctx, namePrefix, filter, limit, exclusiveStartKey)
+		if err != nil {
+			return nil, err
+		}
+		c.storeIfCurrent("", gen, func() { c.searches.Set(key, page) })
+		return page, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.(*SearchPage), nil
+}
+
+func (c *CachingFoodRepository) GetMeasuresForFood(ctx context.Context, foodID string) ([]MeasureItem, error) {
+	if measures, ok := c.measures.Get(foodID); ok {
+		return append([]MeasureItem(nil), measures...), nil
+	}
+	gen := c.generation(foodID)
+	val, err := c.load(ctx, loadKey("measures:"+foodID, gen), func(ctx context.Context) (interface{}, error) {
+		measures, err := c.inner.GetMeasuresForFood(ctx, foodID)
+		if err != nil {
+			return nil, err
+		}
+		c.storeIfCurrent(foodID, gen, func() { c.measures.Set(foodID, measures) })
+		return measures, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]MeasureItem(nil), val.([]MeasureItem)...), nil
+}
+
+func (c *CachingFoodRepository) GetFoodWithMeasures(ctx context.Context, foodID string) (*model.Food, error) {
+	if food, ok := c.foods.Get(foodID); ok {
+		return cloneFood(food), nil
+	}
+	gen := c.generation(foodID)
+	val, err := c.load(ctx, loadKey("food:"+foodID, gen), func(ctx context.Context) (interface{}, error) {
+		food, err := c.inner.GetFoodWithMeasures(ctx, foodID)
+		if err != nil {
+			return nil, err
+		}
+		c.storeIfCurrent(foodID, gen, func() { c.foods.Set(foodID, food) })
+		return food, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneFood(val.(*model.Food)), nil
+}
+
+// GetFoodsWithMeasures serve do cache o que houver e busca o restante num único lote.
+func (c *CachingFoodRepository) GetFoodsWithMeasures(ctx context.Context, foodIDs []string) (map[string]*model.Food, map[string]error, error) {
+	foods := make(map[string]*model.Food, len(foodIDs))
+	var pending []string
+	for _, id := range foodIDs {
+		if food, ok := c.foods.Get(id); ok {
+			foods[id] = cloneFood(food)
+		} else {
+			pending = append(pending, id)
+		}
+	}
+	if len(pending) == 0 {
+		return foods, nil, nil
+	}
+
+	gens := make(map[string]uint64, len(pending))
+	for _, id := range pending {
+		gens[id] = c.generation(id)
+	}
+	loaded, failures, err := c.inner.GetFoodsWithMeasures(ctx, pending)
+	if err != nil {
+		return nil, nil, err
+	}
+	for id, food := range loaded {
+		c.storeIfCurrent(id, gens[id], func() { c.foods.Set(id, food) })
+		foods[id] = cloneFood(food)
+	}
+	return foods, failures, nil
+}
+
+// ListFoods não é cacheado aqui: a TacoView já mantém a lista materializada.
+func (c *CachingFoodRepository) ListFoods(ctx context.Context) ([]TacoFoodItem, error) {
+	return c.inner.ListFoods(ctx)
+}
+
+// ListFoodsPage também não é cacheado: a exportação lê cada página uma única vez.
+func (c *CachingFoodRepository) ListFoodsPage(ctx context.Context, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	return c.inner.ListFoodsPage(ctx, limit, exclusiveStartKey)
+}
+
+func (c *CachingFoodRepository) CreateMeasure(ctx context.Context, measure MeasureItem) (*MeasureItem, error) {
+	created, err := c.inner.CreateMeasure(ctx, measure)
+	if err == nil {
+		c.Invalidate(measure.FoodID)
+	}
+	return created, err
+}
+
+func (c *CachingFoodRepository) UpdateMeasure(ctx context.Context, foodID, measureName string, measure MeasureItem) (*MeasureItem, error) {
+	updated, err := c.inner.UpdateMeasure(ctx, foodID, measureName, measure)
+	if err == nil {
+		c.Invalidate(foodID)
+	}
+	return updated, err
+}
+
+func (c *CachingFoodRepository) DeleteMeasure(ctx context.Context, foodID, measureName string) error {
+	err := c.inner.DeleteMeasure(ctx, foodID, measureName)
+	if err == nil {
+		c.Invalidate(foodID)
+	}
+	return err
+}
+
+// Invalidate remove do cache o alimento e suas medidas e impede que cargas já em andamento
+// gravem a versão anterior.
+func (c *CachingFoodRepository) Invalidate(foodID string) {
+	c.genMu.Lock()
+	defer c.genMu.Unlock()
+	c.generations[foodID]++
+	c.foods.Delete(foodID)
+	c.measures.Delete(foodID)
+}
+
+// Purge esvazia todos os caches e zera as estatísticas.
+func (c *CachingFoodRepository) Purge() {
+	c.genMu.Lock()
+	defer c.genMu.Unlock()
+	c.purges++
+	c.foods.Purge()
+	c.measures.Purge()
+	c.searches.Purge()
+}
+
+func (c *CachingFoodRepository) Stats() []CacheStats {
+	return []CacheStats{c.foods.Stats(), c.measures.Stats(), c.searches.Stats()}
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"saas-nutri/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// gatedFoodRepository conta as leituras e, quando armado, segura a próxima leitura depois de
+// ela já ter lido o dado, como uma carga lenta que termina após uma edição.
+type gatedFoodRepository struct {
+	*InMemoryFoodRepository
+
+	mu      sync.Mutex
+	calls   map[string]int
+	gate    chan struct{}
+	started chan struct{}
+}
+
+func newGatedFoodRepository() *gatedFoodRepository {
+	foods, measures := SampleTacoFoods()
+	return &gatedFoodRepository{InMemoryFoodRepository: NewInMemoryFoodRepository(foods, measures), calls: map[string]int{}}
+}
+
+// arm faz a próxima leitura esperar por release; started fecha quando ela já leu o dado.
+func (r *gatedFoodRepository) arm() (started <-chan struct{}, release func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gate = make(chan struct{})
+	r.started = make(chan struct{})
+	gate := r.gate
+	return r.started, func() { close(gate) }
+}
+
+func (r *gatedFoodRepository) read(op string) {
+	r.mu.Lock()
+	r.calls[op]++
+	gate, started := r.gate, r.started
+	r.gate, r.started = nil, nil
+	r.mu.Unlock()
+	if gate != nil {
+		close(started)
+		<-gate
+	}
+}
+
+func (r *gatedFoodRepository) count(op string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[op]
+}
+
+func (r *gatedFoodRepository) GetFoodWithMeasures(ctx context.Context, foodID string) (*model.Food, error) {
+	food, err := r.InMemoryFoodRepository.GetFoodWithMeasures(ctx, foodID)
+	r.read("food")
+	return food, err
+}
+
+func (r *gatedFoodRepository) GetMeasuresForFood(ctx context.Context, foodID string) ([]MeasureItem, error) {
+	measures, err := r.InMemoryFoodRepository.GetMeasuresForFood(ctx, foodID)
+	r.read("measures")
+	return measures, err
+}
+
+func (r *gatedFoodRepository) SearchFoodsByNamePrefix(ctx context.Context, namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	page, err := r.InMemoryFoodRepository.SearchFoodsByNamePrefix(ctx, namePrefix, filter, limit, exclusiveStartKey)
+	r.read("search")
+	return page, err
+}
+
+func spoonGrams(t *testing.T, measures []model.HouseholdMeasure) float64 {
+	t.Helper()
+	for _, m := range measures {
+		if m.Key == "colher de sopa cheia" {
+			return m.Grams
+		}
+	}
+	t.Fatalf("medida 'colher de sopa cheia' ausente: %+v", measures)
+	return 0
+}
+
+func TestCachingFoodRepositoryDropsLoadStartedBeforeEdit(t *testing.T) {
+	ctx := context.Background()
+	for _, edit := range []bool{false, true} {
+		inner := newGatedFoodRepository()
+		cache := NewCachingFoodRepository(inner, 10, time.Hour)
+
+		started, release := inner.arm()
+		done := make(chan *model.Food)
+		go func() {
+			food, err := cache.GetFoodWithMeasures(ctx, "taco-3")
+			if err != nil {
+				t.Errorf("GetFoodWithMeasures: %v", err)
+			}
+			done <- food
+		}()
+		<-started
+		if edit {
+			if _, err := cache.UpdateMeasure(ctx, "taco-3", "colher de sopa cheia", MeasureItem{MeasureQuantity: "1", GramEquivalent: 30}); err != nil {
+				t.Fatalf("UpdateMeasure: %v", err)
+			}
+		}
+		release()
+		if stale := <-done; stale == nil || spoonGrams(t, stale.HouseholdMeasures) != 25 {
+			t.Fatalf("a carga em andamento devolve o que leu: %+v", stale)
+		}
+
+		food, err := cache.GetFoodWithMeasures(ctx, "taco-3")
+		if err != nil {
+			t.Fatalf("GetFoodWithMeasures: %v", err)
+		}
+		wantGrams, wantCalls := 25.0, 1
+		if edit {
+			wantGrams, wantCalls = 30, 2
+		}
+		if got := spoonGrams(t, food.HouseholdMeasures); got != wantGrams {
+			t.Errorf("edição %t: colher com %g g; esperava %g", edit, got, wantGrams)
+		}
+		if got := inner.count("food"); got != wantCalls {
+			t.Errorf("edição %t: %d leituras do repositório; esperava %d", edit, got, wantCalls)
+		}
+	}
+}
+
+func TestCachingFoodRepositoryInvalidateDuringMeasuresLoad(t *testing.T) {
+	ctx := context.Background()
+	inner := newGatedFoodRepository()
+	cache := NewCachingFoodRepository(inner, 10, time.Hour)
+
+	started, release := inner.arm()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := cache.GetMeasuresForFood(ctx, "taco-3"); err != nil {
+			t.Errorf("GetMeasuresForFood: %v", err)
+		}
+	}()
+	<-started
+	cache.Invalidate("taco-3")
+	release()
+	<-done
+
+	if _, err := cache.GetMeasuresForFood(ctx, "taco-3"); err != nil {
+		t.Fatalf("GetMeasuresForFood: %v", err)
+	}
+	if got := inner.count("measures"); got != 2 {
+		t.Errorf("carga anterior ao Invalidate não deveria ficar no cache: %d leituras, esperava 2", got)
+	}
+	if _, err := cache.GetMeasuresForFood(ctx, "taco-3"); err != nil {
+		t.Fatalf("GetMeasuresForFood: %v", err)
+	}
+	if got := inner.count("measures"); got != 2 {
+		t.Errorf("carga posterior ao Invalidate deveria ficar no cache: %d leituras, esperava 2", got)
+	}
+}
+
+func TestCachingFoodRepositoryPurgeDuringSearchLoad(t *testing.T) {
+	ctx := context.Background()
+	inner := newGatedFoodRepository()
+	cache := NewCachingFoodRepository(inner, 10, time.Hour)
+
+	started, release := inner.arm()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := cache.SearchFoodsByNamePrefix(ctx, "arroz", SearchFilter{}, 10, nil); err != nil {
+			t.Errorf("SearchFoodsByNamePrefix: %v", err)
+		}
+	}()
+	<-started
+	cache.Purge()
+	release()
+	<-done
+
+	if _, err := cache.SearchFoodsByNamePrefix(ctx, "arroz", SearchFilter{}, 10, nil); err != nil {
+		t.Fatalf("SearchFoodsByNamePrefix: %v", err)
+	}
+	if got := inner.count("search"); got != 2 {
+		t.Errorf("busca anterior ao Purge não deveria ficar no cache: %d leituras, esperava 2", got)
+	}
+}
+
+func TestCachingFoodRepositoryReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	inner := newGatedFoodRepository()
+	cache := NewCachingFoodRepository(inner, 10, time.Hour)
+
+	first, err := cache.GetFoodWithMeasures(ctx, "taco-3")
+	if err != nil {
+		t.Fatalf("GetFoodWithMeasures: %v", err)
+	}
+	if first.Tags == nil {
+		t.Fatalf("alimento TACO deveria vir com tags")
+	}
+	first.Tags.Allergens = append(first.Tags.Allergens, model.AllergenGluten)
+	first.Tags.Diets = nil
+	first.HouseholdMeasures[0].Grams = 999
+
+	second, err := cache.GetFoodWithMeasures(ctx, "taco-3")
+	if err != nil {
+		t.Fatalf("GetFoodWithMeasures: %v", err)
+	}
+	if second.Tags == first.Tags || second.Tags.HasAllergen(model.AllergenGluten) || len(second.Tags.Diets) == 0 {
+		t.Errorf("alterar as tags devolvidas não deveria mexer no cache: %+v", second.Tags)
+	}
+	if second.HouseholdMeasures[0].Grams != 1 {
+		t.Errorf("alterar as medidas devolvidas não deveria mexer no cache: %+v", second.HouseholdMeasures)
+	}
+	if got := inner.count("food"); got != 1 {
+		t.Errorf("segunda leitura deveria vir do cache: %d leituras", got)
+	}
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrInvalidCursor = errors.New("cursor de paginação inválido")
+
+// CursorCodec transforma a chave de início exclusiva do DynamoDB em um cursor opaco
+// assinado com HMAC, para que o cliente não consiga forjar ou alterar a posição.
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret string) (*CursorCodec, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("erro ao gerar segredo dos cursores: %w", err)
+		}
+	}
+	return &CursorCodec{secret: key}, nil
+}
+
+// cursorScopeField guarda, no payload assinado, o escopo da busca que gerou o cursor. O "$"
+// não aparece em nomes de atributos das chaves.
+const cursorScopeField = "$scope"
+
+// CursorScope resume a busca (rota, parâmetros, tenant, idioma) que um cursor continua. Um
+// cursor só é aceito pela mesma busca que o gerou: com outro termo ou outros filtros, a
+// posição guardada nele não faz sentido.
+func CursorScope(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
+
+func (c *CursorCodec) Encode(key map[string]types.AttributeValue, scope string) (string, error) {
+	plain := make(map[string]string, len(key)+1)
+	for name, value := range key {
+		s, ok := value.(*types.AttributeValueMemberS)
+		if !ok {
+			return "", fmt.Errorf("atributo de chave '%s' com tipo não suportado no cursor", name)
+		}
+		plain[name] = s.Value
+	}
+	plain[cursorScopeField] = scope
+
+	payload, err := json.Marshal(plain)
+	if err != nil {
+		return "", fmt.Errorf("erro ao serializar cursor: %w", err)
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signature := base64.RawURLEncoding.EncodeToString(c.sign(payload))
+	return encodedPayload + "." + signature, nil
+}
+
+// Decode devolve a chave guardada no cursor, ou ErrInvalidCursor se ele foi alterado ou
+// gerado por uma busca com outro escopo.
+func (c *CursorCodec) Decode(cursor, scope string) (map[string]types.AttributeValue, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(cursor, ".")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var plain map[string]string
+	if err := json.Unmarshal(payload, &plain); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if plain[cursorScopeField] != scope {
+		return nil, fmt.Errorf("%w: cursor gerado por outra busca", ErrInvalidCursor)
+	}
+	delete(plain, cursorScopeField)
+
+	key := make(map[string]types.AttributeValue, len(plain))
+	for name, value := range plain {
+		key[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return key, nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
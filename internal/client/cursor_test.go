@@ -0,0 +1,95 @@
+package client
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec, err := NewCursorCodec("segredo")
+	if err != nil {
+		t.Fatalf("NewCursorCodec: %v", err)
+	}
+	otherSecret, err := NewCursorCodec("outro-segredo")
+	if err != nil {
+		t.Fatalf("NewCursorCodec: %v", err)
+	}
+
+	key := map[string]types.AttributeValue{
+		"food_id":         &types.AttributeValueMemberS{Value: "taco-3"},
+		"data_source":     &types.AttributeValueMemberS{Value: "TACO"},
+		"normalized_name": &types.AttributeValueMemberS{Value: "arroz tipo 1 cozido"},
+	}
+	scope := CursorScope("/api/foods", "search=arroz", "clinica-1", "pt")
+	cursor, err := codec.Encode(key, scope)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	payload, signature, _ := strings.Cut(cursor, ".")
+
+	// Troca o food_id no payload e reaproveita a assinatura original.
+	rawPayload, _ := base64.RawURLEncoding.DecodeString(payload)
+	forged := base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(rawPayload), "taco-3", "taco-9", 1)))
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+
+	cases := []struct {
+		name   string
+		codec  *CursorCodec
+		cursor string
+		scope  string
+		want   map[string]types.AttributeValue
+	}{
+		{name: "ida e volta", codec: codec, cursor: cursor, scope: scope, want: key},
+		{name: "payload adulterado", codec: codec, cursor: forged + "." + signature, scope: scope},
+		{name: "assinatura adulterada", codec: codec, cursor: payload + "." + string(flipped), scope: scope},
+		{name: "sem assinatura", codec: codec, cursor: payload, scope: scope},
+		{name: "base64 inválido", codec: codec, cursor: "!!!." + signature, scope: scope},
+		{name: "outro segredo", codec: otherSecret, cursor: cursor, scope: scope},
+		{name: "outra busca", codec: codec, cursor: cursor, scope: CursorScope("/api/foods", "search=feijao", "clinica-1", "pt")},
+		{name: "outro tenant", codec: codec, cursor: cursor, scope: CursorScope("/api/foods", "search=arroz", "clinica-2", "pt")},
+		{name: "outro idioma", codec: codec, cursor: cursor, scope: CursorScope("/api/foods", "search=arroz", "clinica-1", "es")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.codec.Decode(c.cursor, c.scope)
+			if c.want == nil {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("esperava ErrInvalidCursor, veio %v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Decode = %v; esperava %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCursorScopeSeparatesParts(t *testing.T) {
+	if CursorScope("ab", "c") == CursorScope("a", "bc") {
+		t.Errorf("escopos com partes diferentes não deveriam coincidir")
+	}
+	if CursorScope("/api/foods", "search=arroz") != CursorScope("/api/foods", "search=arroz") {
+		t.Errorf("o mesmo escopo deveria ser determinístico")
+	}
+}
+
+func TestCursorCodecRejectsNonStringKeys(t *testing.T) {
+	codec, err := NewCursorCodec("")
+	if err != nil {
+		t.Fatalf("NewCursorCodec: %v", err)
+	}
+	_, err = codec.Encode(map[string]types.AttributeValue{"energy_kcal": &types.AttributeValueMemberN{Value: "128"}}, "")
+	if err == nil {
+		t.Errorf("chave numérica deveria ser recusada")
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"saas-nutri/internal/model"
+	"sort"
+	"sync"
+)
+
+type InMemoryCustomFoodRepository struct {
+	mu    sync.RWMutex
+	items map[string]map[string]CustomFoodItem
+}
+
+func NewInMemoryCustomFoodRepository() *InMemoryCustomFoodRepository {
+	return &InMemoryCustomFoodRepository{items: make(map[string]map[string]CustomFoodItem)}
+}
+
+func (r *InMemoryCustomFoodRepository) CreateCustomFood(ctx context.Context, tenantID string, food model.Food) (*model.Food, error) {
+	if err := ValidateCustomFood(food); err != nil {
+		return nil, err
+	}
+	foodID, err := newCustomFoodID()
+	if err != nil {
+		return nil, err
+	}
+
+	item := newCustomFoodItem(tenantID, foodID, food)
+
+	r.mu.Lock()
+	if r.items[tenantID] == nil {
+		r.items[tenantID] = make(map[string]CustomFoodItem)
+	}
+	r.items[tenantID][foodID] = item
+	r.mu.Unlock()
+
+	created := item.toFood()
+	return &created, nil
+}
+
+func (r *InMemoryCustomFoodRepository) GetCustomFood(ctx context.Context, tenantID, foodID string) (*model.Food, error) {
+	r.mu.RLock()
+	item, ok := r.items[tenantID][foodID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	food := item.toFood()
+	return &food, nil
+}
+
+func (r *InMemoryCustomFoodRepository) tenantItems(tenantID string) []CustomFoodItem {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]CustomFoodItem, 0, len(r.items[tenantID]))
+	for _, item := range r.items[tenantID] {
+		items = append(items, item)
+	}
+	return items
+}
+
+func (r *InMemoryCustomFoodRepository) ListCustomFoods(ctx context.Context, tenantID string) ([]model.Food, error) {
+	items := r.tenantItems(tenantID)
+	sort.Slice(items, func(i, j int) bool { return items[i].NormalizedName < items[j].NormalizedName })
+
+	foods := make([]model.Food, 0, len(items))
+	for _, item := range items {
+		foods = append(foods, item.toFood())
+	}
+	return foods, nil
+}
+
+func (r *InMemoryCustomFoodRepository) UpdateCustomFood(ctx context.Context, tenantID, foodID string, food model.Food) (*model.Food, error) {
+	if err := ValidateCustomFood(food); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[tenantID][foodID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	item := newCustomFoodItem(tenantID, foodID, food)
+	r.items[tenantID][foodID] = item
+
+	updated := item.toFood()
+	return &updated, nil
+}
+
+func (r *InMemoryCustomFoodRepository) DeleteCustomFood(ctx context.Context, tenantID, foodID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[tenantID][foodID]; !ok {
+		return fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	delete(r.items[tenantID], foodID)
+	return nil
+}
+
+func (r *InMemoryCustomFoodRepository) SearchCustomFoods(ctx context.Context, tenantID, query string, limit int) ([]model.Food, error) {
+	if normalizeString(query) == "" {
+		return []model.Food{}, nil
+	}
+	return rankCustomFoods(query, r.tenantItems(tenantID), limit), nil
+}
@@ -0,0 +1,362 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"saas-nutri/internal/model"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	SourceCustom       = "CUSTOM"
+	customFoodIDPrefix = "custom-"
+)
+
+var ErrInvalidFood = errors.New("alimento inválido")
+
+// CustomFoodRepository guarda alimentos cadastrados pelas clínicas. Toda operação recebe o
+// tenant e nunca devolve alimentos de outro tenant.
+type CustomFoodRepository interface {
+	CreateCustomFood(ctx context.Context, tenantID string, food model.Food) (*model.Food, error)
+	GetCustomFood(ctx context.Context, tenantID, foodID string) (*model.Food, error)
+	ListCustomFoods(ctx context.Context, tenantID string) ([]model.Food, error)
+	UpdateCustomFood(ctx context.Context, tenantID, foodID string, food model.Food) (*model.Food, error)
+	DeleteCustomFood(ctx context.Context, tenantID, foodID string) error
+	SearchCustomFoods(ctx context.Context, tenantID, query string, limit int) ([]model.Food, error)
+}
+
+// CustomFoodItem é o item da tabela CustomFoods (chave: tenant_id + food_id).
+type CustomFoodItem struct {
+	TenantID          string                  `dynamodbav:"tenant_id"`
+	FoodID            string                  `dynamodbav:"food_id"`
+	Name              string                  `dynamodbav:"name"`
+	NormalizedName    string                  `dynamodbav:"normalized_name"`
+	FoodGroup         string                  `dynamodbav:"food_group,omitempty"`
+	EnergyKcal        float64                 `dynamodbav:"energy_kcal"`
+	ProteinG          float64                 `dynamodbav:"protein_g"`
+	CarbohydrateG     float64                 `dynamodbav:"carbohydrate_g"`
+	FatG              float64                 `dynamodbav:"fat_g"`
+	FiberG            float64                 `dynamodbav:"fiber_g"`
+	Nutrients         map[string]TacoNutrient `dynamodbav:"nutrients,omitempty"`
+	HouseholdMeasures []CustomMeasure         `dynamodbav:"household_measures,omitempty"`
+	UpdatedAt         string                  `dynamodbav:"updated_at"`
+}
+
+type CustomMeasure struct {
+	Name  string  `dynamodbav:"name"`
+	Grams float64 `dynamodbav:"grams"`
+}
+
+func IsCustomFoodID(foodID string) bool {
+	return strings.HasPrefix(foodID, customFoodIDPrefix)
+}
+
+func newCustomFoodID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("erro ao gerar id do alimento: %w", err)
+	}
+	return customFoodIDPrefix + hex.EncodeToString(b), nil
+}
+
+func ValidateCustomFood(food model.Food) error {
+	if strings.TrimSpace(food.Name) == "" {
+		return fmt.Errorf("%w: nome é obrigatório", ErrInvalidFood)
+	}
+	if food.FoodGroup != "" && !model.IsFoodGroup(food.FoodGroup) {
+		return fmt.Errorf("%w: grupo de alimentos desconhecido '%s'", ErrInvalidFood, food.FoodGroup)
+	}
+	for field, value := range map[string]float64{
+		"energy_kcal":    food.EnergyKcal,
+		"protein_g":      food.ProteinG,
+		"carbohydrate_g": food.CarbohydrateG,
+		"fat_g":          food.FatG,
+		"fiber_g":        food.FiberG,
+	} {
+		if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+			return fmt.Errorf("%w: %s deve ser um número não negativo", ErrInvalidFood, field)
+		}
+	}
+	for key, n := range food.Nutrients {
+		if model.NutrientUnit(key) == "" {
+			return fmt.Errorf("%w: nutriente desconhecido '%s'", ErrInvalidFood, key)
+		}
+		switch n.Status {
+		case model.NutrientStatusMeasured, "":
+			if n.Value == nil || *n.Value < 0 {
+				return fmt.Errorf("%w: nutriente '%s' precisa de valor não negativo", ErrInvalidFood, key)
+			}
+		case model.NutrientStatusTrace, model.NutrientStatusNotAnalyzed:
+		default:
+			return fmt.Errorf("%w: status inválido para o nutriente '%s'", ErrInvalidFood, key)
+		}
+	}
+	for _, m := range food.HouseholdMeasures {
+		if strings.TrimSpace(m.Name) == "" || m.Grams <= 0 {
+			return fmt.Errorf("%w: medidas caseiras precisam de nome e gramas positivos", ErrInvalidFood)
+		}
+	}
+	return nil
+}
+
+func newCustomFoodItem(tenantID, foodID string, food model.Food) CustomFoodItem {
+	item := CustomFoodItem{
+		TenantID:       tenantID,
+		FoodID:         foodID,
+		Name:           strings.TrimSpace(food.Name),
+		NormalizedName: normalizeString(food.Name),
+		FoodGroup:      food.FoodGroup,
+		EnergyKcal:     food.EnergyKcal,
+		ProteinG:       food.ProteinG,
+		CarbohydrateG:  food.CarbohydrateG,
+		FatG:           food.FatG,
+		FiberG:         food.FiberG,
+		UpdatedAt:      time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(food.Nutrients) > 0 {
+		item.Nutrients = make(map[string]TacoNutrient, len(food.Nutrients))
+		for key, n := range food.Nutrients {
+			status := n.Status
+			if status == "" {
+				status = model.NutrientStatusMeasured
+			}
+			stored := TacoNutrient{Status: status}
+			if status == model.NutrientStatusMeasured {
+				stored.Value = n.Value
+			}
+			item.Nutrients[key] = stored
+		}
+	}
+	for _, m := range food.HouseholdMeasures {
+		if normalizeString(m.Name) == defaultMeasureName {
+			continue
+		}
+		item.HouseholdMeasures = append(item.HouseholdMeasures, CustomMeasure{Name: strings.TrimSpace(m.Name), Grams: m.Grams})
+	}
+	return item
+}
+
+func (c CustomFoodItem) toFood() model.Food {
+	food := model.Food{
+		Id:                c.FoodID,
+		Name:              c.Name,
+		Source:            SourceCustom,
+		FoodGroup:         c.FoodGroup,
+		EnergyKcal:        c.EnergyKcal,
+		ProteinG:          c.ProteinG,
+		CarbohydrateG:     c.CarbohydrateG,
+		FatG:              c.FatG,
+		FiberG:            c.FiberG,
+		Nutrients:         mapTacoNutrients(c.Nutrients),
+		HouseholdMeasures: []model.HouseholdMeasure{model.GramMeasure()},
+	}
+	for _, m := range c.HouseholdMeasures {
+		food.HouseholdMeasures = append(food.HouseholdMeasures, model.HouseholdMeasure{Name: m.Name, Grams: m.Grams})
+	}
+	food.Tags = RuleTags(food)
+	return food
+}
+
+// rankCustomFoods aplica a mesma correspondência da busca TACO aos alimentos próprios.
+func rankCustomFoods(query string, items []CustomFoodItem, limit int) []model.Food {
+	queryTokens := tokenize(query)
+
+	type ranked struct {
+		item  CustomFoodItem
+		score float64
+	}
+	var matches []ranked
+	for _, item := range items {
+		if score, ok := matchScore(queryTokens, item.Name); ok {
+			matches = append(matches, ranked{item: item, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].item.NormalizedName < matches[j].item.NormalizedName
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	foods := make([]model.Food, 0, len(matches))
+	for _, m := range matches {
+		foods = append(foods, m.item.toFood())
+	}
+	return foods
+}
+
+type DynamoCustomFoodRepository struct {
+	DB        *dynamodb.Client
+	TableName string
+}
+
+func NewDynamoCustomFoodRepository(db *dynamodb.Client, tableName string) *DynamoCustomFoodRepository {
+	return &DynamoCustomFoodRepository{DB: db, TableName: tableName}
+}
+
+func customFoodKey(tenantID, foodID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		"food_id":   &types.AttributeValueMemberS{Value: foodID},
+	}
+}
+
+func (r *DynamoCustomFoodRepository) CreateCustomFood(ctx context.Context, tenantID string, food model.Food) (*model.Food, error) {
+	if err := ValidateCustomFood(food); err != nil {
+		return nil, err
+	}
+	foodID, err := newCustomFoodID()
+	if err != nil {
+		return nil, err
+	}
+
+	item := newCustomFoodItem(tenantID, foodID, food)
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar alimento próprio: %w", err)
+	}
+
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(food_id)"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar alimento próprio no DynamoDB: %w", err)
+	}
+
+	log.Printf("Alimento próprio %s criado para o tenant %s", foodID, tenantID)
+	created := item.toFood()
+	return &created, nil
+}
+
+func (r *DynamoCustomFoodRepository) GetCustomFood(ctx context.Context, tenantID, foodID string) (*model.Food, error) {
+	result, err := r.DB.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       customFoodKey(tenantID, foodID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alimento próprio no DynamoDB: %w", err)
+	}
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+
+	var item CustomFoodItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar alimento próprio: %w", err)
+	}
+	food := item.toFood()
+	return &food, nil
+}
+
+func (r *DynamoCustomFoodRepository) queryTenant(ctx context.Context, tenantID string) ([]CustomFoodItem, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.TableName),
+		KeyConditionExpression: aws.String("tenant_id = :tid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":tid": &types.AttributeValueMemberS{Value: tenantID},
+		},
+	}
+
+	var items []CustomFoodItem
+	paginator := dynamodb.NewQueryPaginator(r.DB, queryInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar alimentos próprios no DynamoDB: %w", err)
+		}
+		var pageItems []CustomFoodItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("erro ao deserializar alimentos próprios: %w", err)
+		}
+		items = append(items, pageItems...)
+	}
+	return items, nil
+}
+
+func (r *DynamoCustomFoodRepository) ListCustomFoods(ctx context.Context, tenantID string) ([]model.Food, error) {
+	items, err := r.queryTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].NormalizedName < items[j].NormalizedName })
+
+	foods := make([]model.Food, 0, len(items))
+	for _, item := range items {
+		foods = append(foods, item.toFood())
+	}
+	return foods, nil
+}
+
+func (r *DynamoCustomFoodRepository) UpdateCustomFood(ctx context.Context, tenantID, foodID string, food model.Food) (*model.Food, error) {
+	if err := ValidateCustomFood(food); err != nil {
+		return nil, err
+	}
+
+	item := newCustomFoodItem(tenantID, foodID, food)
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar alimento próprio: %w", err)
+	}
+
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_exists(food_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao atualizar alimento próprio no DynamoDB: %w", err)
+	}
+
+	updated := item.toFood()
+	return &updated, nil
+}
+
+func (r *DynamoCustomFoodRepository) DeleteCustomFood(ctx context.Context, tenantID, foodID string) error {
+	_, err := r.DB.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.TableName),
+		Key:                 customFoodKey(tenantID, foodID),
+		ConditionExpression: aws.String("attribute_exists(food_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao remover alimento próprio no DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// SearchCustomFoods lê a partição do tenant e ranqueia em memória; cada clínica tem poucos
+// alimentos próprios, então a leitura da partição inteira é barata.
+func (r *DynamoCustomFoodRepository) SearchCustomFoods(ctx context.Context, tenantID, query string, limit int) ([]model.Food, error) {
+	if normalizeString(query) == "" {
+		return []model.Food{}, nil
+	}
+	items, err := r.queryTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return rankCustomFoods(query, items, limit), nil
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"saas-nutri/internal/model"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DefaultFallbackCooldown é quanto tempo o repositório fica em modo degradado depois de uma
+// falha do principal, antes de tentar o principal de novo.
+const DefaultFallbackCooldown = 30 * time.Second
+
+// FallbackFoodRepository lê do repositório principal (DynamoDB) e, quando ele falha por
+// credencial, rede ou indisponibilidade, responde com o repositório reserva (snapshot
+// embutido). Erros de negócio, como alimento inexistente ou cursor inválido, não acionam a
+// reserva. Escritas de medidas vão apenas para o principal.
+type FallbackFoodRepository struct {
+	primary  FoodRepository
+	fallback FoodRepository
+	cooldown time.Duration
+
+	mu            sync.Mutex
+	degradedUntil time.Time
+}
+
+func NewFallbackFoodRepository(primary, fallback FoodRepository, cooldown time.Duration) *FallbackFoodRepository {
+	if cooldown <= 0 {
+		cooldown = DefaultFallbackCooldown
+	}
+	return &FallbackFoodRepository{primary: primary, fallback: fallback, cooldown: cooldown}
+}
+
+// Degraded informa se as leituras estão sendo atendidas pela reserva.
+func (r *FallbackFoodRepository) Degraded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Now().Before(r.degradedUntil)
+}
+
+func isBusinessError(err error) bool {
+	return errors.Is(err, ErrFoodNotFound) || errors.Is(err, ErrInvalidCursor) ||
+		errors.Is(err, context.Canceled)
+}
+
+// useFallback registra a falha do principal e decide se a leitura deve ir para a reserva.
+func (r *FallbackFoodRepository) useFallback(operation string, err error) bool {
+	if isBusinessError(err) {
+		return false
+	}
+	r.mu.Lock()
+	entering := !time.Now().Before(r.degradedUntil)
+	r.degradedUntil = time.Now().Add(r.cooldown)
+	r.mu.Unlock()
+
+	if entering {
+		log.Printf("Aviso: %s falhou no repositório principal, usando snapshot embutido por %s: %v", operation, r.cooldown, err)
+	}
+	return true
+}
+
+func fallbackRead[T any](r *FallbackFoodRepository, operation string, read func(FoodRepository) (T, error)) (T, error) {
+	if r.Degraded() {
+		return read(r.fallback)
+	}
+	result, err := read(r.primary)
+	if err != nil && r.useFallback(operation, err) {
+		return read(r.fallback)
+	}
+	return result, err
+}
+
+func (r *FallbackFoodRepository) SearchFoodsByNamePrefix(ctx context.Context, namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	return fallbackRead(r, "busca", func(repo FoodRepository) (*SearchPage, error) {
+		return repo.SearchFoodsByNamePrefix(ctx, namePrefix, filter, limit, exclusiveStartKey)
+	})
+}
+
+func (r *FallbackFoodRepository) GetMeasuresForFood(ctx context.Context, foodID string) ([]MeasureItem, error) {
+	return fallbackRead(r, "busca de medidas", func(repo FoodRepository) ([]MeasureItem, error) {
+		return repo.GetMeasuresForFood(ctx, foodID)
+	})
+}
+
+func (r *FallbackFoodRepository) GetFoodWithMeasures(ctx context.Context, foodID string) (*model.Food, error) {
+	return fallbackRead(r, "busca de alimento", func(repo FoodRepository) (*model.Food, error) {
+		return repo.GetFoodWithMeasures(ctx, foodID)
+	})
+}
+
+// GetFoodsWithMeasures só recorre à reserva quando o lote inteiro falha; falhas de medidas
+// de alguns ids voltam como estão.
+func (r *FallbackFoodRepository) GetFoodsWithMeasures(ctx context.Context, foodIDs []string) (map[string]*model.Food, map[string]error, error) {
+	type batch struct {
+		foods    map[string]*model.Food
+		failures map[string]error
+	}
+	result, err := fallbackRead(r, "busca em lote", func(repo FoodRepository) (batch, error) {
+		foods, failures, err := repo.GetFoodsWithMeasures(ctx, foodIDs)
+		return batch{foods, failures}, err
+	})
+	return result.foods, result.failures, err
+}
+
+func (r *FallbackFoodRepository) ListFoods(ctx context.Context) ([]TacoFoodItem, error) {
+	return fallbackRead(r, "listagem", func(repo FoodRepository) ([]TacoFoodItem, error) {
+		return repo.ListFoods(ctx)
+	})
+}
+
+// ListFoodsPage segue o cursor pelo food_id, que os dois repositórios entendem. Se a troca
+// acontecer no meio de uma leitura, a ordem muda e o cursor pode não ser encontrado
+// (ErrInvalidCursor); quem percorre a base deve recomeçar.
+func (r *FallbackFoodRepository) ListFoodsPage(ctx context.Context, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	return fallbackRead(r, "listagem paginada", func(repo FoodRepository) (*SearchPage, error) {
+		return repo.ListFoodsPage(ctx, limit, exclusiveStartKey)
+	})
+}
+
+func (r *FallbackFoodRepository) CreateMeasure(ctx context.Context, measure MeasureItem) (*MeasureItem, error) {
+	return r.primary.CreateMeasure(ctx, measure)
+}
+
+func (r *FallbackFoodRepository) UpdateMeasure(ctx context.Context, foodID, measureName string, measure MeasureItem) (*MeasureItem, error) {
+	return r.primary.UpdateMeasure(ctx, foodID, measureName, measure)
+}
+
+func (r *FallbackFoodRepository) DeleteMeasure(ctx context.Context, foodID, measureName string) error {
+	return r.primary.DeleteMeasure(ctx, foodID, measureName)
+}
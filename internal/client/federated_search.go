@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"saas-nutri/internal/locale"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/tenant"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SourceStatusOK      = "ok"
+	SourceStatusError   = "error"
+	SourceStatusTimeout = "timeout"
+	// SourceStatusUnavailable indica que a fonte foi poupada ou recusou a chamada (circuito
+	// aberto, limite de requisições ou falhas seguidas), em vez de responder sem resultados.
+	SourceStatusUnavailable = "unavailable"
+)
+
+var ErrUnknownSource = errors.New("fonte de alimentos desconhecida")
+
+type SearchSource struct {
+	Name    string
+	Timeout time.Duration
+	Search  func(ctx context.Context, query string, filter SearchFilter) ([]model.Food, error)
+}
+
+func RepositorySource(name string, repo FoodRepository, timeout time.Duration) SearchSource {
+	return SearchSource{
+		Name:    name,
+		Timeout: timeout,
+		Search: func(ctx context.Context, query string, filter SearchFilter) ([]model.Food, error) {
+			page, err := repo.SearchFoodsByNamePrefix(ctx, query, filter, MaxSearchLimit, nil)
+			if err != nil {
+				return nil, err
+			}
+			foods := make([]model.Food, 0, len(page.Items))
+			for _, item := range page.Items {
+				foods = append(foods, MapTacoToFood(item))
+			}
+			return foods, nil
+		},
+	}
+}
+
+// CustomFoodSource busca os alimentos próprios do tenant presente no contexto.
+// Sem tenant autenticado a fonte simplesmente não retorna itens.
+func CustomFoodSource(name string, repo CustomFoodRepository, timeout time.Duration) SearchSource {
+	return SearchSource{
+		Name:    name,
+		Timeout: timeout,
+		Search: func(ctx context.Context, query string, filter SearchFilter) ([]model.Food, error) {
+			tenantID, ok := tenant.FromContext(ctx)
+			if !ok {
+				return []model.Food{}, nil
+			}
+			foods, err := repo.SearchCustomFoods(ctx, tenantID, query, MaxSearchLimit)
+			if err != nil {
+				return nil, err
+			}
+			return filter.FilterFoods(foods), nil
+		},
+	}
+}
+
+// RecipeSource busca as receitas do tenant presente no contexto.
+func RecipeSource(name string, repo RecipeRepository, timeout time.Duration) SearchSource {
+	return SearchSource{
+		Name:    name,
+		Timeout: timeout,
+		Search: func(ctx context.Context, query string, filter SearchFilter) ([]model.Food, error) {
+			tenantID, ok := tenant.FromContext(ctx)
+			if !ok {
+				return []model.Food{}, nil
+			}
+			foods, err := repo.SearchRecipes(ctx, tenantID, query, MaxSearchLimit)
+			if err != nil {
+				return nil, err
+			}
+			return filter.FilterFoods(foods), nil
+		},
+	}
+}
+
+// APIClientSource adapta um FoodAPIClient; o timeout da fonte chega ao cliente pelo contexto.
+func APIClientSource(name string, apiClient FoodAPIClient, timeout time.Duration) SearchSource {
+	return SearchSource{
+		Name:    name,
+		Timeout: timeout,
+		Search: func(ctx context.Context, query string, filter SearchFilter) ([]model.Food, error) {
+			foods, err := apiClient.SearchFoods(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+			return filter.FilterFoods(foods), nil
+		},
+	}
+}
+
+type FederatedSearcher struct {
+	sources map[string]SearchSource
+	order   []string
+	// Translator traduz os resultados antes da mescla, para que a deduplicação e o ranking
+	// usem os nomes no idioma pedido.
+	Translator *Translator
+}
+
+func NewFederatedSearcher(sources ...SearchSource) *FederatedSearcher {
+	f := &FederatedSearcher{sources: make(map[string]SearchSource)}
+	for _, s := range sources {
+		f.sources[s.Name] = s
+		f.order = append(f.order, s.Name)
+	}
+	return f
+}
+
+func (f *FederatedSearcher) SourceNames() []string {
+	return append([]string(nil), f.order...)
+}
+
+type sourceResult struct {
+	foods  []model.Food
+	status model.SourceStatus
+}
+
+// Search consulta todas as fontes selecionadas em paralelo, cada uma com seu próprio timeout,
+// e devolve uma lista única ranqueada. Falhas de uma fonte aparecem apenas nos status.
+func (f *FederatedSearcher) Search(ctx context.Context, query string, sourceNames []string, filter SearchFilter, limit int) ([]model.Food, []model.SourceStatus, error) {
+	selected := make([]SearchSource, 0, len(sourceNames))
+	seen := make(map[string]bool)
+	for _, name := range sourceNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		source, ok := f.sources[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownSource, name)
+		}
+		seen[name] = true
+		selected = append(selected, source)
+	}
+	if len(selected) == 0 {
+		return nil, nil, fmt.Errorf("%w: nenhuma fonte selecionada", ErrUnknownSource)
+	}
+
+	results := make([]sourceResult, len(selected))
+	var wg sync.WaitGroup
+	for i, source := range selected {
+		wg.Add(1)
+		go func(i int, source SearchSource) {
+			defer wg.Done()
+			results[i] = runSource(ctx, source, query, filter)
+		}(i, source)
+	}
+	wg.Wait()
+
+	statuses := make([]model.SourceStatus, 0, len(results))
+	var perSource [][]model.Food
+	for _, res := range results {
+		if f.Translator != nil {
+			l := locale.FromContext(ctx)
+			for i := range res.foods {
+				f.Translator.Localize(ctx, l, &res.foods[i])
+			}
+		}
+		statuses = append(statuses, res.status)
+		perSource = append(perSource, res.foods)
+	}
+
+	merged := mergeFederatedResults(query, perSource)
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+
+	return merged, statuses, nil
+}
+
+func runSource(ctx context.Context, source SearchSource, query string, filter SearchFilter) sourceResult {
+	sourceCtx, cancel := context.WithTimeout(ctx, source.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	foods, err := source.Search(sourceCtx, query, filter)
+	status := model.SourceStatus{
+		Source:     source.Name,
+		Status:     SourceStatusOK,
+		Count:      len(foods),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		log.Printf("Fonte '%s' falhou na busca federada: %v", source.Name, err)
+		status.Count = 0
+		status.Status = SourceStatusError
+		status.Error = "falha ao consultar a fonte"
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			status.Status = SourceStatusTimeout
+			status.Error = fmt.Sprintf("tempo limite de %s excedido", source.Timeout)
+		case errors.Is(err, ErrUpstreamUnavailable):
+			status.Status = SourceStatusUnavailable
+			status.Error = "fonte indisponível no momento"
+		}
+		return sourceResult{status: status}
+	}
+
+	return sourceResult{foods: foods, status: status}
+}
+
+type rankedFood struct {
+	food     model.Food
+	score    float64
+	priority int
+}
+
+// mergeFederatedResults remove duplicados pelo nome normalizado, mantendo o item da fonte
+// de maior prioridade (ordem da seleção), e ordena pela qualidade da correspondência.
+func mergeFederatedResults(query string, perSource [][]model.Food) []model.Food {
+	queryTokens := tokenize(query)
+	byName := make(map[string]rankedFood)
+
+	for priority, foods := range perSource {
+		for _, food := range foods {
+			key := normalizeString(food.Name)
+			if key == "" {
+				continue
+			}
+			score, ok := matchScore(queryTokens, food.Name)
+			if !ok && food.MatchedSynonym != "" {
+				score, ok = matchScore(tokenize(food.MatchedSynonym), food.Name)
+				score *= synonymScoreFactor
+			}
+			if !ok {
+				// A fonte externa pode ter sua própria lógica de relevância; mantemos com score baixo.
+				score = 0
+			}
+			if existing, found := byName[key]; found && existing.priority <= priority {
+				continue
+			}
+			byName[key] = rankedFood{food: food, score: score, priority: priority}
+		}
+	}
+
+	ranked := make([]rankedFood, 0, len(byName))
+	for _, r := range byName {
+		ranked = append(ranked, r)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		if ranked[i].priority != ranked[j].priority {
+			return ranked[i].priority < ranked[j].priority
+		}
+		return ranked[i].food.Name < ranked[j].food.Name
+	})
+
+	merged := make([]model.Food, 0, len(ranked))
+	for _, r := range ranked {
+		merged = append(merged, r.food)
+	}
+	return merged
+}
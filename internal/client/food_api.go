@@ -1,22 +1,57 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"regexp"
 	"saas-nutri/internal/model"
 	"strconv"
+	"strings"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 type FoodAPIClient interface {
-	SearchFoods(query string) ([]model.Food, error)
+	SearchFoods(ctx context.Context, query string) ([]model.Food, error)
+}
+
+type BarcodeClient interface {
+	GetProductByBarcode(ctx context.Context, barcode string) (*model.Food, error)
+}
+
+type OpenFoodFactsClient interface {
+	FoodAPIClient
+	BarcodeClient
 }
 
+var ErrProductNotFound = errors.New("produto não encontrado")
+
 
-const offBaseURL = "https://world.openfoodfacts.org/cgi/search.pl"
+const offBaseURL = "https://world.openfoodfacts.org"
+
+const (
+	offSearchPath  = "/cgi/search.pl"
+	offProductPath = "/api/v2/product/"
+	offUserAgent   = "SaaS Nutri MVP - Golang Client - v0.1"
+	offSourceName  = "Open Food Facts"
+
+	// Campos pedidos à OFF. allergens_tags e ingredients_analysis_tags alimentam as tags de
+	// alérgenos e dietas; sem eles o produto fica sem tags (desconhecido).
+	offSearchFields  = "_id,code,product_name,nutriments,allergens_tags,ingredients_analysis_tags"
+	offProductFields = "code,product_name,nutriments,serving_size,serving_quantity,allergens_tags,ingredients_analysis_tags"
+)
+
+// Limites da política de uso da OFF: 10 buscas e 100 leituras de produto por minuto por IP.
+var (
+	offSearchRate  = rate.Every(time.Minute / 10)
+	offProductRate = rate.Every(time.Minute / 100)
+)
 
 type offResponse struct {
 	Products []offProduct `json:"products"`
@@ -24,9 +59,19 @@ type offResponse struct {
 }
 
 type offProduct struct {
-	ID          string        `json:"_id"`
-	ProductName string        `json:"product_name"`
-	Nutriments  offNutriments `json:"nutriments"`
+	ID              string        `json:"_id"`
+	Code            string        `json:"code"`
+	ProductName     string        `json:"product_name"`
+	Nutriments      offNutriments `json:"nutriments"`
+	ServingSize     string        `json:"serving_size"`
+	ServingQuantity interface{}   `json:"serving_quantity"`
+	AllergensTags   []string      `json:"allergens_tags"`
+	AnalysisTags    []string      `json:"ingredients_analysis_tags"`
+}
+
+type offProductResponse struct {
+	Status  int        `json:"status"`
+	Product offProduct `json:"product"`
 }
 
 type offNutriments struct {
@@ -35,54 +80,120 @@ type offNutriments struct {
 	Carbohyates100g interface{} `json:"carbohydrates_100g"`
 	Fat100g         interface{} `json:"fat_100g"`
 	Fiber100g       interface{} `json:"fiber_100g"`
+
+	Sodium100g             interface{} `json:"sodium_100g"`
+	Potassium100g          interface{} `json:"potassium_100g"`
+	Calcium100g            interface{} `json:"calcium_100g"`
+	Iron100g               interface{} `json:"iron_100g"`
+	Zinc100g               interface{} `json:"zinc_100g"`
+	Magnesium100g          interface{} `json:"magnesium_100g"`
+	Phosphorus100g         interface{} `json:"phosphorus_100g"`
+	VitaminA100g           interface{} `json:"vitamin-a_100g"`
+	VitaminC100g           interface{} `json:"vitamin-c_100g"`
+	VitaminB1100g          interface{} `json:"vitamin-b1_100g"`
+	VitaminB2100g          interface{} `json:"vitamin-b2_100g"`
+	VitaminB6100g          interface{} `json:"vitamin-b6_100g"`
+	VitaminPP100g          interface{} `json:"vitamin-pp_100g"`
+	Cholesterol100g        interface{} `json:"cholesterol_100g"`
+	SaturatedFat100g       interface{} `json:"saturated-fat_100g"`
+	MonounsaturatedFat100g interface{} `json:"monounsaturated-fat_100g"`
+	PolyunsaturatedFat100g interface{} `json:"polyunsaturated-fat_100g"`
+}
+
+// micronutrients converte os nutrientes da OFF (sempre em g/100 g) para as unidades do
+// model.NutrientDefinitions. Nutrientes não informados pela OFF ficam fora do mapa.
+func (n offNutriments) micronutrients() map[string]model.NutrientValue {
+	const (
+		toMg  = 1000.0
+		toMcg = 1000000.0
+		toG   = 1.0
+	)
+	sources := []struct {
+		key    string
+		value  interface{}
+		factor float64
+	}{
+		{model.NutrientSodium, n.Sodium100g, toMg},
+		{model.NutrientPotassium, n.Potassium100g, toMg},
+		{model.NutrientCalcium, n.Calcium100g, toMg},
+		{model.NutrientIron, n.Iron100g, toMg},
+		{model.NutrientZinc, n.Zinc100g, toMg},
+		{model.NutrientMagnesium, n.Magnesium100g, toMg},
+		{model.NutrientPhosphorus, n.Phosphorus100g, toMg},
+		{model.NutrientVitaminA, n.VitaminA100g, toMcg},
+		{model.NutrientVitaminC, n.VitaminC100g, toMg},
+		{model.NutrientVitaminB1, n.VitaminB1100g, toMg},
+		{model.NutrientVitaminB2, n.VitaminB2100g, toMg},
+		{model.NutrientVitaminB6, n.VitaminB6100g, toMg},
+		{model.NutrientVitaminB3, n.VitaminPP100g, toMg},
+		{model.NutrientCholesterol, n.Cholesterol100g, toMg},
+		{model.NutrientSaturatedFat, n.SaturatedFat100g, toG},
+		{model.NutrientMonounsaturatedFat, n.MonounsaturatedFat100g, toG},
+		{model.NutrientPolyunsaturatedFat, n.PolyunsaturatedFat100g, toG},
+	}
+
+	nutrients := make(map[string]model.NutrientValue)
+	for _, src := range sources {
+		if src.value == nil {
+			continue
+		}
+		nutrients[src.key] = model.MeasuredNutrient(src.key, parseFloatOrZero(src.value)*src.factor)
+	}
+	if len(nutrients) == 0 {
+		return nil
+	}
+	return nutrients
 }
 
+// offClient consulta a OFF com limite de taxa do lado do cliente, novas tentativas e circuit
+// breaker. Falhas da fonte voltam como *UpstreamError; busca sem resultados volta lista vazia.
 type offClient struct {
-	httpClient *http.Client
-	baseURL    string
+	http           *resilientHTTPClient
+	baseURL        string
+	searchLimiter  *rate.Limiter
+	productLimiter *rate.Limiter
 }
 
-func NewOpenFoodFactsClient() FoodAPIClient {
+func NewOpenFoodFactsClient() OpenFoodFactsClient {
+	return NewOpenFoodFactsClientWithBaseURL(offBaseURL)
+}
+
+func NewOpenFoodFactsClientWithBaseURL(baseURL string) OpenFoodFactsClient {
 	return &offClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-		baseURL:    offBaseURL,
+		http:           newResilientHTTPClient(offSourceName, offUserAgent, 10*time.Second),
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		searchLimiter:  rate.NewLimiter(offSearchRate, 5),
+		productLimiter: rate.NewLimiter(offProductRate, 10),
 	}
 }
 
-func (c *offClient) SearchFoods(query string) ([]model.Food, error) {
-	apiURL, _ := url.Parse(c.baseURL)
+func (c *offClient) SearchFoods(ctx context.Context, query string) ([]model.Food, error) {
+	apiURL, _ := url.Parse(c.baseURL + offSearchPath)
 	params := url.Values{}
 	params.Add("search_terms", query)
 	params.Add("search_simple", "1")
 	params.Add("action", "process")
 	params.Add("json", "1")                         
 	params.Add("page_size", "10")                   
-	params.Add("fields", "product_name,nutriments") 
+	params.Add("fields", offSearchFields)
 	apiURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", apiURL.String(), nil)
+	resp, err := c.http.get(ctx, c.searchLimiter, apiURL.String())
 	if err != nil {
-		log.Printf("Erro ao criar request para OFF: %v", err)
-		return nil, fmt.Errorf("erro interno ao preparar busca")
-	}
-	req.Header.Set("User-Agent", "SaaS Nutri MVP - Golang Client - v0.1")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		log.Printf("Erro ao fazer request para OFF: %v", err)
-		return nil, fmt.Errorf("erro ao conectar com API de alimentos")
+		log.Printf("Erro ao buscar na OFF: %v", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		log.Printf("Erro status code da OFF API: %d", resp.StatusCode)
-		return nil, fmt.Errorf("API de alimentos retornou erro %d", resp.StatusCode)
+		return nil, &UpstreamError{Source: offSourceName, Kind: ErrUpstreamBadResponse, StatusCode: resp.StatusCode}
 	}
 
 	var offResp offResponse
 	if err := json.NewDecoder(resp.Body).Decode(&offResp); err != nil {
 		log.Printf("Erro ao decodificar JSON da OFF: %v", err)
-		return nil, fmt.Errorf("erro ao ler resposta da API de alimentos")
+		return nil, &UpstreamError{Source: offSourceName, Kind: ErrUpstreamBadResponse, Err: err}
 	}
 
 	foods := make([]model.Food, 0, len(offResp.Products))
@@ -90,8 +201,12 @@ func (c *offClient) SearchFoods(query string) ([]model.Food, error) {
 		if p.ProductName == "" { 
 			continue
 		}
+		id := p.ID
+		if id == "" {
+			id = p.Code
+		}
 		foods = append(foods, model.Food{
-			Id:            p.ID,
+			Id:            id,
 			Name:          p.ProductName,
 			Source:        "OpenFoodFacts",
 			EnergyKcal:    parseFloatOrZero(p.Nutriments.EnergyKcal100g),
@@ -99,12 +214,98 @@ func (c *offClient) SearchFoods(query string) ([]model.Food, error) {
 			CarbohydrateG: parseFloatOrZero(p.Nutriments.Carbohyates100g),
 			FatG:          parseFloatOrZero(p.Nutriments.Fat100g),
 			FiberG:        parseFloatOrZero(p.Nutriments.Fiber100g),
+			Nutrients:     p.Nutriments.micronutrients(),
+			Tags:          offTags(p.AllergensTags, p.AnalysisTags),
 		})
 	}
 
 	return foods, nil
 }
 
+func (c *offClient) GetProductByBarcode(ctx context.Context, barcode string) (*model.Food, error) {
+	apiURL, err := url.Parse(c.baseURL + offProductPath + url.PathEscape(barcode))
+	if err != nil {
+		log.Printf("Erro ao montar URL de produto da OFF: %v", err)
+		return nil, fmt.Errorf("erro interno ao preparar busca")
+	}
+	params := url.Values{}
+	params.Add("fields", offProductFields)
+	apiURL.RawQuery = params.Encode()
+
+	resp, err := c.http.get(ctx, c.productLimiter, apiURL.String())
+	if err != nil {
+		log.Printf("Erro ao buscar produto na OFF: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrProductNotFound, barcode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Erro status code da OFF API (produto): %d", resp.StatusCode)
+		return nil, &UpstreamError{Source: offSourceName, Kind: ErrUpstreamBadResponse, StatusCode: resp.StatusCode}
+	}
+
+	var productResp offProductResponse
+	if err := json.NewDecoder(resp.Body).Decode(&productResp); err != nil {
+		log.Printf("Erro ao decodificar JSON de produto da OFF: %v", err)
+		return nil, &UpstreamError{Source: offSourceName, Kind: ErrUpstreamBadResponse, Err: err}
+	}
+	if productResp.Status != 1 {
+		return nil, fmt.Errorf("%w: %s", ErrProductNotFound, barcode)
+	}
+
+	p := productResp.Product
+	if p.Code == "" {
+		p.Code = barcode
+	}
+	food := &model.Food{
+		Id:            p.Code,
+		Name:          p.ProductName,
+		Source:        "OpenFoodFacts",
+		EnergyKcal:    parseFloatOrZero(p.Nutriments.EnergyKcal100g),
+		ProteinG:      parseFloatOrZero(p.Nutriments.Proteins100g),
+		CarbohydrateG: parseFloatOrZero(p.Nutriments.Carbohyates100g),
+		FatG:          parseFloatOrZero(p.Nutriments.Fat100g),
+		FiberG:        parseFloatOrZero(p.Nutriments.Fiber100g),
+		Nutrients:     p.Nutriments.micronutrients(),
+		Tags:          offTags(p.AllergensTags, p.AnalysisTags),
+		HouseholdMeasures: []model.HouseholdMeasure{
+			model.GramMeasure(),
+		},
+	}
+	if serving, ok := servingMeasure(p.ServingSize, p.ServingQuantity); ok {
+		food.HouseholdMeasures = append(food.HouseholdMeasures, serving)
+	}
+
+	return food, nil
+}
+
+var servingSizePattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(g|gr|gramas?|ml)\b`)
+
+// servingMeasure converte a porção declarada na embalagem em medida caseira.
+// Para líquidos em ml, assume densidade 1 g/ml, como a própria OFF faz em serving_quantity.
+func servingMeasure(servingSize string, servingQuantity interface{}) (model.HouseholdMeasure, bool) {
+	grams := parseFloatOrZero(servingQuantity)
+	if grams <= 0 {
+		match := servingSizePattern.FindStringSubmatch(servingSize)
+		if match == nil {
+			return model.HouseholdMeasure{}, false
+		}
+		grams = parseFloatOrZero(strings.Replace(match[1], ",", ".", 1))
+	}
+	if grams <= 0 {
+		return model.HouseholdMeasure{}, false
+	}
+
+	name := fmt.Sprintf("1 porção (%s g)", strconv.FormatFloat(grams, 'f', -1, 64))
+	if label := strings.TrimSpace(servingSize); label != "" {
+		name = "1 porção: " + label
+	}
+	return model.HouseholdMeasure{Name: name, Grams: grams}, true
+}
+
 func parseFloatOrZero(value interface{}) float64 {
 	if value == nil {
 		return 0
@@ -131,7 +332,7 @@ func NewMockFoodAPIClient() FoodAPIClient {
 	return &mockFoodAPIClient{}
 }
 
-func (m *mockFoodAPIClient) SearchFoods(query string) ([]model.Food, error) {
+func (m *mockFoodAPIClient) SearchFoods(ctx context.Context, query string) ([]model.Food, error) {
 	log.Printf("Cliente Mock: Buscando por '%s'\n", query)
 	if query == "maçã" {
 		return []model.Food{
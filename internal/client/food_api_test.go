@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+// newOFFStandIn sobe um servidor local no lugar da Open Food Facts. handler recebe o código
+// de barras pedido em /api/v2/product/{code}.
+func newOFFStandIn(t *testing.T, handler func(w http.ResponseWriter, code string)) OpenFoodFactsClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/product/{code}", func(w http.ResponseWriter, r *http.Request) {
+		assertOFFFields(t, r, "code", "product_name", "nutriments", "serving_size", "serving_quantity", "allergens_tags", "ingredients_analysis_tags")
+		handler(w, r.PathValue("code"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return NewOpenFoodFactsClientWithBaseURL(server.URL)
+}
+
+// assertOFFFields confere que a requisição pede à OFF todos os campos que o cliente lê.
+func assertOFFFields(t *testing.T, r *http.Request, want ...string) {
+	t.Helper()
+	requested := map[string]bool{}
+	for _, field := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		requested[field] = true
+	}
+	for _, field := range want {
+		if !requested[field] {
+			t.Errorf("fields = %q não pede %q", r.URL.Query().Get("fields"), field)
+		}
+	}
+}
+
+func TestGetProductByBarcodeFound(t *testing.T) {
+	client := newOFFStandIn(t, func(w http.ResponseWriter, code string) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status": 1, "product": {
+			"code": %q,
+			"product_name": "Biscoito de maisena",
+			"serving_size": "6 biscoitos (30 g)",
+			"serving_quantity": "30",
+			"nutriments": {"energy-kcal_100g": 443, "proteins_100g": "8.1", "carbohydrates_100g": 74, "fat_100g": 12.5, "fiber_100g": 2.1, "sodium_100g": 0.35}
+		}}`, code)
+	})
+
+	food, err := client.GetProductByBarcode(context.Background(), "7891000100103")
+	if err != nil {
+		t.Fatalf("GetProductByBarcode: %v", err)
+	}
+	if food.Id != "7891000100103" || food.Name != "Biscoito de maisena" || food.Source != "OpenFoodFacts" {
+		t.Errorf("identificação inesperada: %+v", food)
+	}
+	if food.EnergyKcal != 443 || food.ProteinG != 8.1 || food.CarbohydrateG != 74 || food.FatG != 12.5 || food.FiberG != 2.1 {
+		t.Errorf("macronutrientes inesperados: %+v", food)
+	}
+	if sodium := food.Nutrients["sodium"]; sodium.Value == nil || *sodium.Value != 350 || sodium.Unit != "mg" {
+		t.Errorf("sódio deveria ser 350 mg, veio %+v", sodium)
+	}
+	if len(food.HouseholdMeasures) != 2 {
+		t.Fatalf("esperava grama e a porção da embalagem, veio %+v", food.HouseholdMeasures)
+	}
+	if serving := food.HouseholdMeasures[1]; serving.Name != "1 porção: 6 biscoitos (30 g)" || serving.Grams != 30 {
+		t.Errorf("porção inesperada: %+v", serving)
+	}
+}
+
+func TestGetProductByBarcodeNotFound(t *testing.T) {
+	cases := map[string]func(w http.ResponseWriter, code string){
+		"status 404": func(w http.ResponseWriter, code string) {
+			http.Error(w, `{"status": 0}`, http.StatusNotFound)
+		},
+		"status 0": func(w http.ResponseWriter, code string) {
+			fmt.Fprintf(w, `{"status": 0, "status_verbose": "product not found", "code": %q}`, code)
+		},
+	}
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := newOFFStandIn(t, handler)
+			_, err := client.GetProductByBarcode(context.Background(), "7891000100103")
+			if !errors.Is(err, ErrProductNotFound) {
+				t.Fatalf("esperava ErrProductNotFound, veio %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	cases := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{code: "7891000100103", want: "7891000100103"},
+		{code: " 7891000 100103 ", want: "7891000100103"},
+		{code: "96385074", want: "96385074"},
+		{code: "036000291452", want: "036000291452"},
+		{code: "7891000100104", wantErr: true},
+		{code: "96385075", wantErr: true},
+		{code: "789100010010", wantErr: true},
+		{code: "78910001001a3", wantErr: true},
+		{code: "", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := NormalizeGTIN(c.code)
+		if c.wantErr {
+			if !errors.Is(err, ErrInvalidGTIN) {
+				t.Errorf("NormalizeGTIN(%q): esperava ErrInvalidGTIN, veio %q, %v", c.code, got, err)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("NormalizeGTIN(%q) = %q, %v; esperava %q", c.code, got, err, c.want)
+		}
+	}
+}
+
+func TestServingMeasure(t *testing.T) {
+	cases := []struct {
+		servingSize     string
+		servingQuantity interface{}
+		wantName        string
+		wantGrams       float64
+		wantOK          bool
+	}{
+		{servingSize: "30 g", wantName: "1 porção: 30 g", wantGrams: 30, wantOK: true},
+		{servingSize: "2 fatias (25,5 g)", wantName: "1 porção: 2 fatias (25,5 g)", wantGrams: 25.5, wantOK: true},
+		{servingSize: "1 copo 200ml", wantName: "1 porção: 1 copo 200ml", wantGrams: 200, wantOK: true},
+		{servingSize: "40 gramas", wantName: "1 porção: 40 gramas", wantGrams: 40, wantOK: true},
+		{servingSize: "", servingQuantity: 45.0, wantName: "1 porção (45 g)", wantGrams: 45, wantOK: true},
+		{servingSize: "1 unidade", servingQuantity: "12", wantName: "1 porção: 1 unidade", wantGrams: 12, wantOK: true},
+		{servingSize: "1 unidade"},
+		{servingSize: "0 g"},
+	}
+	for _, c := range cases {
+		got, ok := servingMeasure(c.servingSize, c.servingQuantity)
+		if ok != c.wantOK {
+			t.Errorf("servingMeasure(%q, %v): ok = %t, esperava %t", c.servingSize, c.servingQuantity, ok, c.wantOK)
+			continue
+		}
+		if ok && (got.Name != c.wantName || got.Grams != c.wantGrams) {
+			t.Errorf("servingMeasure(%q, %v) = %+v; esperava %q com %g g", c.servingSize, c.servingQuantity, got, c.wantName, c.wantGrams)
+		}
+	}
+}
+
+func TestGetProductByBarcodeTags(t *testing.T) {
+	cases := map[string]struct {
+		extra string
+		want  *model.FoodTags
+	}{
+		"com alérgenos e análise": {
+			extra: `"allergens_tags": ["en:gluten", "en:milk", "en:mustard"], "ingredients_analysis_tags": ["en:palm-oil-free", "en:vegetarian"]`,
+			want: &model.FoodTags{
+				Allergens: []string{model.AllergenGluten, model.AllergenLactose},
+				Diets:     []string{model.DietVegetarian},
+				Source:    model.TagSourceOpenFoodFacts,
+			},
+		},
+		"sem alérgenos declarados": {
+			extra: `"allergens_tags": [], "ingredients_analysis_tags": ["en:vegan"]`,
+			want: &model.FoodTags{
+				Allergens: []string{},
+				Diets:     []string{model.DietVegan, model.DietVegetarian},
+				Source:    model.TagSourceOpenFoodFacts,
+			},
+		},
+		"sem os campos na resposta": {
+			extra: `"ingredients_analysis_tags": ["en:vegan"]`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := newOFFStandIn(t, func(w http.ResponseWriter, code string) {
+				fmt.Fprintf(w, `{"status": 1, "product": {"code": %q, "product_name": "Biscoito", "nutriments": {}, %s}}`, code, c.extra)
+			})
+			food, err := client.GetProductByBarcode(context.Background(), "7891000100103")
+			if err != nil {
+				t.Fatalf("GetProductByBarcode: %v", err)
+			}
+			if !reflect.DeepEqual(food.Tags, c.want) {
+				t.Errorf("tags = %+v; esperava %+v", food.Tags, c.want)
+			}
+		})
+	}
+}
+
+func TestSearchFoodsRequestsTags(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /cgi/search.pl", func(w http.ResponseWriter, r *http.Request) {
+		assertOFFFields(t, r, "_id", "code", "product_name", "nutriments", "allergens_tags", "ingredients_analysis_tags")
+		fmt.Fprint(w, `{"count": 3, "products": [
+			{"_id": "7891000100103", "code": "7891000100103", "product_name": "Biscoito de maisena", "nutriments": {"energy-kcal_100g": 443},
+			 "allergens_tags": ["en:gluten"], "ingredients_analysis_tags": ["en:vegetarian"]},
+			{"code": "7896004000855", "product_name": "Suco de uva", "nutriments": {}},
+			{"code": "7890000000000", "nutriments": {}}
+		]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	foods, err := NewOpenFoodFactsClientWithBaseURL(server.URL).SearchFoods(context.Background(), "biscoito")
+	if err != nil {
+		t.Fatalf("SearchFoods: %v", err)
+	}
+	if len(foods) != 2 {
+		t.Fatalf("esperava 2 produtos com nome, veio %+v", foods)
+	}
+	wantTags := &model.FoodTags{Allergens: []string{model.AllergenGluten}, Diets: []string{model.DietVegetarian}, Source: model.TagSourceOpenFoodFacts}
+	if foods[0].Id != "7891000100103" || !reflect.DeepEqual(foods[0].Tags, wantTags) {
+		t.Errorf("primeiro produto inesperado: %+v (tags %+v)", foods[0], foods[0].Tags)
+	}
+	if foods[1].Id != "7896004000855" || foods[1].Tags != nil {
+		t.Errorf("produto sem allergens_tags deveria ficar sem tags e usar code como id: %+v", foods[1])
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"saas-nutri/internal/model"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// MaxBatchFoods limita os ids aceitos por POST /api/foods/batch.
+	MaxBatchFoods = 100
+
+	// batchGetChunkSize é o máximo de chaves por chamada BatchGetItem.
+	batchGetChunkSize   = 100
+	maxBatchGetAttempts = 6
+	// measureQueryWorkers limita as Queries de medidas em paralelo num lote.
+	measureQueryWorkers = 8
+)
+
+// GetFoodsWithMeasures busca vários alimentos com BatchGetItem (em blocos de 100 chaves,
+// repetindo UnprocessedKeys com backoff) e as medidas de cada um em paralelo. Ids não
+// encontrados ficam fora do mapa devolvido; uma falha nas medidas de um alimento só afeta
+// esse id.
+func (r *TacoRepository) GetFoodsWithMeasures(ctx context.Context, foodIDs []string) (map[string]*model.Food, map[string]error, error) {
+	items := make(map[string]TacoFoodItem, len(foodIDs))
+	for start := 0; start < len(foodIDs); start += batchGetChunkSize {
+		end := min(start+batchGetChunkSize, len(foodIDs))
+		if err := r.batchGetTacoItems(ctx, foodIDs[start:end], items); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	foods, failures := loadMeasuresConcurrently(ctx, items, r.GetMeasuresForFood)
+	return foods, failures, nil
+}
+
+func (r *TacoRepository) batchGetTacoItems(ctx context.Context, foodIDs []string, into map[string]TacoFoodItem) error {
+	keys := make([]map[string]types.AttributeValue, 0, len(foodIDs))
+	for _, id := range foodIDs {
+		keys = append(keys, map[string]types.AttributeValue{
+			"food_id": &types.AttributeValueMemberS{Value: id},
+		})
+	}
+	pending := map[string]types.KeysAndAttributes{r.TableName: {Keys: keys}}
+
+	backoff := 50 * time.Millisecond
+	for attempt := 1; attempt <= maxBatchGetAttempts; attempt++ {
+		result, err := r.DB.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: pending})
+		if err != nil {
+			return fmt.Errorf("erro ao executar BatchGetItem no DynamoDB: %w", err)
+		}
+
+		var pageItems []TacoFoodItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Responses[r.TableName], &pageItems); err != nil {
+			return fmt.Errorf("erro ao fazer unmarshal dos resultados do DynamoDB: %w", err)
+		}
+		for _, item := range pageItems {
+			into[item.FoodID] = item
+		}
+
+		unprocessed := result.UnprocessedKeys[r.TableName]
+		if len(unprocessed.Keys) == 0 {
+			return nil
+		}
+		log.Printf("BatchGetItem devolveu %d chaves não processadas, nova tentativa em %s (%d/%d)",
+			len(unprocessed.Keys), backoff, attempt, maxBatchGetAttempts)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		pending = map[string]types.KeysAndAttributes{r.TableName: unprocessed}
+	}
+	return fmt.Errorf("BatchGetItem não processou todas as chaves após %d tentativas", maxBatchGetAttempts)
+}
+
+// loadMeasuresConcurrently busca as medidas dos itens com no máximo measureQueryWorkers
+// chamadas simultâneas e monta os alimentos. Os itens cujas medidas falharam ficam fora de
+// foods e vão para failures.
+func loadMeasuresConcurrently(ctx context.Context, items map[string]TacoFoodItem, getMeasures func(context.Context, string) ([]MeasureItem, error)) (foods map[string]*model.Food, failures map[string]error) {
+	type result struct {
+		id   string
+		food *model.Food
+		err  error
+	}
+
+	ids := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(measureQueryWorkers, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				measures, err := getMeasures(ctx, id)
+				if err != nil {
+					results <- result{id: id, err: fmt.Errorf("erro ao buscar medidas caseiras de %s: %w", id, err)}
+					continue
+				}
+				results <- result{id: id, food: foodWithMeasures(items[id], measures)}
+			}
+		}()
+	}
+
+	go func() {
+		for id := range items {
+			ids <- id
+		}
+		close(ids)
+		wg.Wait()
+		close(results)
+	}()
+
+	foods = make(map[string]*model.Food, len(items))
+	failures = map[string]error{}
+	for res := range results {
+		if res.err != nil {
+			failures[res.id] = res.err
+			continue
+		}
+		foods[res.id] = res.food
+	}
+	return foods, failures
+}
+
+// firstFailure devolve o erro do menor id com falha, para quem precisa de todos os alimentos
+// (exportação, validação) e não consegue seguir com parte deles.
+func firstFailure(failures map[string]error) error {
+	var firstID string
+	for id := range failures {
+		if firstID == "" || id < firstID {
+			firstID = id
+		}
+	}
+	if firstID == "" {
+		return nil
+	}
+	return failures[firstID]
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"saas-nutri/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// exportPageSize é quantos alimentos a exportação lê e monta por vez.
+const exportPageSize = MaxSearchLimit
+
+// ExportFoods percorre os alimentos TACO página a página e entrega cada página a emit, já
+// filtrada e com as medidas caseiras, na ordem do repositório. Com query, percorre as páginas
+// da busca (em ordem de relevância). Só uma página fica em memória por vez; um erro de emit
+// interrompe a leitura e é devolvido.
+func ExportFoods(ctx context.Context, repo FoodRepository, query string, filter SearchFilter, emit func([]model.Food) error) error {
+	var startKey map[string]types.AttributeValue
+	for {
+		var page *SearchPage
+		var err error
+		if query != "" {
+			page, err = repo.SearchFoodsByNamePrefix(ctx, query, filter, exportPageSize, startKey)
+		} else {
+			page, err = repo.ListFoodsPage(ctx, exportPageSize, startKey)
+			if err == nil {
+				page.Items = filter.filterTacoItems(page.Items)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(page.Items) > 0 {
+			items := make(map[string]TacoFoodItem, len(page.Items))
+			for _, item := range page.Items {
+				items[item.FoodID] = item
+			}
+			loaded, failures := loadMeasuresConcurrently(ctx, items, repo.GetMeasuresForFood)
+			if err := firstFailure(failures); err != nil {
+				return err
+			}
+			foods := make([]model.Food, 0, len(page.Items))
+			for _, item := range page.Items {
+				foods = append(foods, *loaded[item.FoodID])
+			}
+			if err := emit(foods); err != nil {
+				return err
+			}
+		}
+
+		if page.LastEvaluatedKey == nil {
+			return nil
+		}
+		startKey = page.LastEvaluatedKey
+	}
+}
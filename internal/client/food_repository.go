@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"saas-nutri/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	DefaultSearchLimit = 25
+	MaxSearchLimit     = 100
+)
+
+var ErrFoodNotFound = errors.New("alimento não encontrado")
+
+// SearchFilter restringe a busca além do termo pesquisado. Campos vazios não filtram.
+// Com ExcludeAllergens ou Diet, alimentos sem tags ficam de fora, pois não há como garantir
+// que atendem à restrição. TagOverrides, quando informado, prevalece sobre as tags calculadas.
+// Synonyms (ver SynonymDictionary.Synonyms) amplia a busca por nome para os sinônimos regionais.
+// Translations (ver Translator.Names) faz a busca usar os nomes do idioma pedido, com o nome em
+// português para os alimentos sem tradução.
+type SearchFilter struct {
+	FoodGroup        string
+	ExcludeAllergens []string
+	Diet             string
+	TagOverrides     map[string]model.FoodTags
+	Synonyms         map[string][]string
+	Translations     *LocalizedNames
+}
+
+// localizedNames devolve os nomes traduzidos do filtro, ou nil para buscar em português.
+func (f SearchFilter) localizedNames() map[string]string {
+	if f.Translations == nil {
+		return nil
+	}
+	return f.Translations.Names
+}
+
+func (f SearchFilter) filtersTags() bool {
+	return len(f.ExcludeAllergens) > 0 || f.Diet != ""
+}
+
+func (f SearchFilter) MatchesFood(food model.Food) bool {
+	if f.FoodGroup != "" && food.FoodGroup != f.FoodGroup {
+		return false
+	}
+	if !f.filtersTags() {
+		return true
+	}
+
+	tags := food.Tags
+	if override, ok := f.TagOverrides[food.Id]; ok {
+		tags = &override
+	}
+	if tags == nil {
+		return false
+	}
+	for _, allergen := range f.ExcludeAllergens {
+		if tags.HasAllergen(allergen) {
+			return false
+		}
+	}
+	return f.Diet == "" || tags.HasDiet(f.Diet)
+}
+
+func (f SearchFilter) matchesTacoItem(item TacoFoodItem) bool {
+	if !f.filtersTags() {
+		return f.FoodGroup == "" || item.FoodGroup == f.FoodGroup
+	}
+	return f.MatchesFood(MapTacoToFood(item))
+}
+
+func (f SearchFilter) filterTacoItems(items []TacoFoodItem) []TacoFoodItem {
+	if f.FoodGroup == "" && !f.filtersTags() {
+		return items
+	}
+	filtered := make([]TacoFoodItem, 0, len(items))
+	for _, item := range items {
+		if f.matchesTacoItem(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func (f SearchFilter) FilterFoods(foods []model.Food) []model.Food {
+	filtered := make([]model.Food, 0, len(foods))
+	for _, food := range foods {
+		if f.MatchesFood(food) {
+			filtered = append(filtered, food)
+		}
+	}
+	return filtered
+}
+
+type SearchPage struct {
+	Items            []TacoFoodItem
+	LastEvaluatedKey map[string]types.AttributeValue
+}
+
+type FoodRepository interface {
+	SearchFoodsByNamePrefix(ctx context.Context, namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error)
+	GetMeasuresForFood(ctx context.Context, foodID string) ([]MeasureItem, error)
+	GetFoodWithMeasures(ctx context.Context, foodID string) (*model.Food, error)
+	// GetFoodsWithMeasures busca vários alimentos de uma vez. Ids não encontrados ficam fora dos
+	// dois mapas; ids cujas medidas não puderam ser lidas vêm em failures, com o erro de cada um.
+	GetFoodsWithMeasures(ctx context.Context, foodIDs []string) (foods map[string]*model.Food, failures map[string]error, err error)
+	// ListFoods devolve todos os alimentos da partição TACO, com nutrientes, sem medidas.
+	ListFoods(ctx context.Context) ([]TacoFoodItem, error)
+	// ListFoodsPage devolve uma página dos alimentos TACO, com nutrientes e sem medidas, a partir
+	// de exclusiveStartKey. É a leitura incremental da exportação; a ordem depende do repositório.
+	ListFoodsPage(ctx context.Context, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error)
+	CreateMeasure(ctx context.Context, measure MeasureItem) (*MeasureItem, error)
+	UpdateMeasure(ctx context.Context, foodID, measureName string, measure MeasureItem) (*MeasureItem, error)
+	DeleteMeasure(ctx context.Context, foodID, measureName string) error
+}
@@ -0,0 +1,299 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"saas-nutri/internal/locale"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/nutrition"
+	"saas-nutri/internal/tenant"
+	"sync"
+)
+
+// maxRecipeDepth limita receitas usadas como ingrediente de outras receitas, evitando ciclos.
+const maxRecipeDepth = 3
+
+// FoodResolver resolve um id de alimento em qualquer origem: TACO, alimento próprio
+// ("custom-..."), receita ("recipe-...") ou USDA FoodData Central ("usda-...").
+// Alimentos próprios e receitas exigem o tenant no contexto.
+type FoodResolver struct {
+	Foods       FoodRepository
+	CustomFoods CustomFoodRepository
+	Recipes     RecipeRepository
+	// USDA resolve ids "usda-..." quando configurado.
+	USDA FoodDetailClient
+	// Tagger aplica as correções manuais de tags, quando configurado.
+	Tagger *FoodTagger
+	// Translator traduz nomes e medidas para o idioma do contexto, quando configurado.
+	Translator *Translator
+}
+
+func NewFoodResolver(foods FoodRepository, customFoods CustomFoodRepository, recipes RecipeRepository) *FoodResolver {
+	return &FoodResolver{Foods: foods, CustomFoods: customFoods, Recipes: recipes}
+}
+
+func (r *FoodResolver) GetFood(ctx context.Context, foodID string) (*model.Food, error) {
+	return r.getFood(ctx, foodID, 0)
+}
+
+func (r *FoodResolver) getFood(ctx context.Context, foodID string, depth int) (*model.Food, error) {
+	food, err := r.lookupFood(ctx, foodID, depth)
+	if err == nil && r.Tagger != nil {
+		r.Tagger.Apply(ctx, food)
+	}
+	if err == nil && r.Translator != nil {
+		r.Translator.Localize(ctx, locale.FromContext(ctx), food)
+	}
+	return food, err
+}
+
+// ApplyTags aplica as correções manuais de tags em alimentos obtidos fora do resolver,
+// como resultados de busca.
+func (r *FoodResolver) ApplyTags(ctx context.Context, foods []model.Food) {
+	if r.Tagger == nil {
+		return
+	}
+	for i := range foods {
+		r.Tagger.Apply(ctx, &foods[i])
+	}
+}
+
+// Localize traduz nomes e medidas de alimentos obtidos fora do resolver para o idioma do contexto.
+func (r *FoodResolver) Localize(ctx context.Context, foods []model.Food) {
+	if r.Translator == nil {
+		return
+	}
+	l := locale.FromContext(ctx)
+	for i := range foods {
+		r.Translator.Localize(ctx, l, &foods[i])
+	}
+}
+
+// localizeMeasures traduz os nomes de exibição das medidas para o idioma do contexto.
+func (r *FoodResolver) localizeMeasures(ctx context.Context, foodID string, measures []MeasureItem) {
+	if r.Translator == nil {
+		return
+	}
+	r.Translator.LocalizeMeasures(ctx, locale.FromContext(ctx), foodID, measures)
+}
+
+// LocalizedNames devolve os nomes traduzidos do idioma do contexto para uso em SearchFilter.
+func (r *FoodResolver) LocalizedNames(ctx context.Context) *LocalizedNames {
+	if r.Translator == nil {
+		return nil
+	}
+	return r.Translator.Names(ctx, locale.FromContext(ctx))
+}
+
+// TagOverrides devolve as correções manuais de tags para uso em SearchFilter.
+func (r *FoodResolver) TagOverrides(ctx context.Context) map[string]model.FoodTags {
+	if r.Tagger == nil {
+		return nil
+	}
+	return r.Tagger.Overrides(ctx)
+}
+
+func (r *FoodResolver) lookupFood(ctx context.Context, foodID string, depth int) (*model.Food, error) {
+	switch {
+	case IsCustomFoodID(foodID):
+		tenantID, ok := tenant.FromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+		}
+		return r.CustomFoods.GetCustomFood(ctx, tenantID, foodID)
+	case IsRecipeID(foodID):
+		tenantID, ok := tenant.FromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+		}
+		if depth >= maxRecipeDepth {
+			return nil, fmt.Errorf("%w: receitas aninhadas em mais de %d níveis", ErrInvalidRecipe, maxRecipeDepth)
+		}
+		recipe, err := r.Recipes.GetRecipe(ctx, tenantID, foodID)
+		if err != nil {
+			return nil, err
+		}
+		food, _, err := r.computeRecipe(ctx, *recipe, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &food, nil
+	case IsUSDAFoodID(foodID) && r.USDA != nil:
+		return r.USDA.GetFood(ctx, foodID)
+	default:
+		return r.Foods.GetFoodWithMeasures(ctx, foodID)
+	}
+}
+
+// GetMeasures devolve as medidas caseiras do alimento. Para TACO vêm da tabela de medidas;
+// para alimentos próprios e receitas, das medidas do próprio alimento.
+func (r *FoodResolver) GetMeasures(ctx context.Context, foodID string) ([]MeasureItem, error) {
+	if isTacoFoodID(foodID) {
+		measures, err := r.Foods.GetMeasuresForFood(ctx, foodID)
+		if err == nil {
+			r.localizeMeasures(ctx, foodID, measures)
+		}
+		return measures, err
+	}
+
+	food, err := r.GetFood(ctx, foodID)
+	if err != nil {
+		return nil, err
+	}
+	return householdToMeasureItems(foodID, food.HouseholdMeasures), nil
+}
+
+func isTacoFoodID(foodID string) bool {
+	return !IsCustomFoodID(foodID) && !IsRecipeID(foodID) && !IsUSDAFoodID(foodID)
+}
+
+// FoodMeasureItems converte as medidas que vieram com o alimento em MeasureItem, sem nova
+// leitura. MeasureName fica com a chave da base quando houver, pois Name pode estar traduzido.
+func FoodMeasureItems(food *model.Food) []MeasureItem {
+	return householdToMeasureItems(food.Id, food.HouseholdMeasures)
+}
+
+func householdToMeasureItems(foodID string, measures []model.HouseholdMeasure) []MeasureItem {
+	items := make([]MeasureItem, 0, len(measures))
+	for _, m := range measures {
+		item := MeasureItem{FoodID: foodID, MeasureName: m.Name, DisplayName: m.Name, GramEquivalent: m.Grams}
+		if m.Key != "" {
+			item.MeasureName = m.Key
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// FindMeasure aceita tanto o nome da medida quanto o nome de exibição, ignorando acentos e caixa.
+func FindMeasure(measures []MeasureItem, name string) (MeasureItem, bool) {
+	wanted := normalizeString(name)
+	for _, m := range measures {
+		if normalizeString(m.MeasureName) == wanted || normalizeString(m.DisplayName) == wanted {
+			return m, true
+		}
+	}
+	return MeasureItem{}, false
+}
+
+// ComputeRecipe resolve os ingredientes e calcula os nutrientes da receita.
+// Devolve a receita como alimento (por 100 g) e o detalhamento.
+func (r *FoodResolver) ComputeRecipe(ctx context.Context, recipe model.Recipe) (model.Food, model.RecipeNutrition, error) {
+	return r.computeRecipe(ctx, recipe, 1)
+}
+
+func (r *FoodResolver) computeRecipe(ctx context.Context, recipe model.Recipe, depth int) (model.Food, model.RecipeNutrition, error) {
+	portions := make([]nutrition.IngredientPortion, 0, len(recipe.Ingredients))
+	for i, ing := range recipe.Ingredients {
+		if ing.FoodID == recipe.Id && recipe.Id != "" {
+			return model.Food{}, model.RecipeNutrition{}, fmt.Errorf("%w: a receita não pode ser ingrediente de si mesma", ErrInvalidRecipe)
+		}
+
+		food, err := r.getFood(ctx, ing.FoodID, depth)
+		if errors.Is(err, ErrFoodNotFound) {
+			return model.Food{}, model.RecipeNutrition{}, fmt.Errorf("%w: ingrediente %d (%s) não encontrado", ErrInvalidRecipe, i+1, ing.FoodID)
+		}
+		if err != nil {
+			return model.Food{}, model.RecipeNutrition{}, err
+		}
+
+		measureLabel := "grama"
+		gramsPerUnit := ing.Grams
+		if ing.MeasureName != "" {
+			measures := householdToMeasureItems(ing.FoodID, food.HouseholdMeasures)
+			if isTacoFoodID(ing.FoodID) {
+				measures, err = r.Foods.GetMeasuresForFood(ctx, ing.FoodID)
+				if err != nil {
+					return model.Food{}, model.RecipeNutrition{}, err
+				}
+			}
+			measure, ok := FindMeasure(measures, ing.MeasureName)
+			if !ok {
+				return model.Food{}, model.RecipeNutrition{}, fmt.Errorf("%w: medida '%s' não encontrada para o ingrediente %d (%s)", ErrInvalidRecipe, ing.MeasureName, i+1, ing.FoodID)
+			}
+			measureLabel = measure.DisplayName
+			gramsPerUnit = measure.GramEquivalent
+		}
+
+		portions = append(portions, nutrition.IngredientPortion{
+			Food:     *food,
+			Measure:  measureLabel,
+			Quantity: ing.Quantity,
+			Grams:    gramsPerUnit * ing.Quantity,
+		})
+	}
+
+	food, result := nutrition.ComputeRecipe(recipe, portions)
+	return food, result, nil
+}
+
+// GetFoods resolve vários ids de uma vez: os da TACO num único lote do repositório e os
+// demais em paralelo pelo caminho de GetFood. Devolve os alimentos encontrados, os ids
+// inexistentes e os erros por id (medidas TACO que falharam ou outras origens fora do ar).
+func (r *FoodResolver) GetFoods(ctx context.Context, foodIDs []string) (map[string]*model.Food, []string, map[string]error, error) {
+	var tacoIDs, otherIDs []string
+	for _, id := range foodIDs {
+		if isTacoFoodID(id) {
+			tacoIDs = append(tacoIDs, id)
+		} else {
+			otherIDs = append(otherIDs, id)
+		}
+	}
+
+	foods := make(map[string]*model.Food, len(foodIDs))
+	failures := map[string]error{}
+	if len(tacoIDs) > 0 {
+		found, tacoFailures, err := r.Foods.GetFoodsWithMeasures(ctx, tacoIDs)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for id, food := range found {
+			foods[id] = food
+		}
+		for id, err := range tacoFailures {
+			failures[id] = err
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range otherIDs {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			food, err := r.lookupFood(ctx, id, 0)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				foods[id] = food
+			case !errors.Is(err, ErrFoodNotFound):
+				failures[id] = err
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	var missing []string
+	for _, id := range foodIDs {
+		if _, ok := foods[id]; !ok {
+			if _, failed := failures[id]; !failed {
+				missing = append(missing, id)
+			}
+		}
+	}
+
+	if r.Tagger != nil {
+		for _, food := range foods {
+			r.Tagger.Apply(ctx, food)
+		}
+	}
+	if r.Translator != nil {
+		l := locale.FromContext(ctx)
+		for _, food := range foods {
+			r.Translator.Localize(ctx, l, food)
+		}
+	}
+	return foods, missing, failures, nil
+}
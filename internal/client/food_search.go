@@ -0,0 +1,244 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeFoodName é a normalização usada tanto na gravação de normalized_name
+// quanto na busca: remove acentos, passa para minúsculas e troca pontuação por espaço.
+func NormalizeFoodName(s string) string {
+	return normalizeString(s)
+}
+
+func normalizeString(s string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, s)
+	if err != nil {
+		folded = s
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(folded) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func tokenize(s string) []string {
+	return strings.Fields(normalizeString(s))
+}
+
+// maxTypos define quantos erros de digitação são tolerados para um termo do tamanho dado.
+func maxTypos(termLen int) int {
+	switch {
+	case termLen <= 3:
+		return 0
+	case termLen <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// tokenScore compara um termo da busca com uma palavra do nome do alimento.
+// Retorna 0 quando não há correspondência.
+func tokenScore(queryToken, nameToken string) float64 {
+	if queryToken == nameToken {
+		return 1.0
+	}
+	if strings.HasPrefix(nameToken, queryToken) {
+		return 0.9
+	}
+
+	allowed := maxTypos(len([]rune(queryToken)))
+	if allowed == 0 {
+		return 0
+	}
+	if d := levenshtein(queryToken, nameToken); d <= allowed {
+		return 0.8 - 0.1*float64(d)
+	}
+
+	// Erro de digitação em um termo ainda incompleto: compara com o início da palavra.
+	nameRunes := []rune(nameToken)
+	queryLen := len([]rune(queryToken))
+	if len(nameRunes) > queryLen {
+		if d := levenshtein(queryToken, string(nameRunes[:queryLen])); d <= allowed {
+			return 0.7 - 0.1*float64(d)
+		}
+	}
+	return 0
+}
+
+// matchScore avalia o quanto um nome de alimento corresponde à busca. Todos os termos
+// da busca precisam corresponder a alguma palavra do nome, em qualquer ordem.
+func matchScore(queryTokens []string, name string) (float64, bool) {
+	if len(queryTokens) == 0 {
+		return 0, false
+	}
+	nameTokens := tokenize(name)
+	if len(nameTokens) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, qt := range queryTokens {
+		best := 0.0
+		for _, nt := range nameTokens {
+			if s := tokenScore(qt, nt); s > best {
+				best = s
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	score := total / float64(len(queryTokens))
+
+	// Prioriza nomes que começam pelo primeiro termo e nomes mais curtos (mais específicos).
+	if tokenScore(queryTokens[0], nameTokens[0]) >= 0.9 {
+		score += 0.2
+	}
+	score += 0.1 * float64(len(queryTokens)) / float64(len(nameTokens))
+
+	return score, true
+}
+
+// synonymScoreFactor deixa correspondências por sinônimo logo abaixo das diretas de mesma qualidade.
+const synonymScoreFactor = 0.95
+
+type rankedTacoItem struct {
+	item  TacoFoodItem
+	score float64
+}
+
+// rankTacoItems filtra os itens que correspondem à busca, ou a uma de suas variantes por
+// sinônimo, e os ordena por qualidade da correspondência. Itens encontrados apenas por
+// sinônimo saem com MatchedSynonym preenchido. Com names (food_id para nome normalizado em
+// outro idioma), a busca usa o nome traduzido quando existe.
+func rankTacoItems(query string, synonyms map[string][]string, names map[string]string, items []TacoFoodItem) []TacoFoodItem {
+	queryTokens := tokenize(query)
+	variants := expandQuery(strings.Join(queryTokens, " "), synonyms)
+
+	var ranked []rankedTacoItem
+	for _, item := range items {
+		name := item.NormalizedName
+		if name == "" {
+			name = item.OriginalName
+		}
+		if localized, ok := names[item.FoodID]; ok {
+			name = localized
+		}
+		best, matched := matchScore(queryTokens, name)
+		for _, v := range variants {
+			score, ok := matchScore(v.tokens, name)
+			if !ok || score*synonymScoreFactor <= best {
+				continue
+			}
+			best, matched = score*synonymScoreFactor, true
+			item.MatchedSynonym = v.synonym
+		}
+		if matched {
+			ranked = append(ranked, rankedTacoItem{item: item, score: best})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		if ranked[i].item.NormalizedName != ranked[j].item.NormalizedName {
+			return ranked[i].item.NormalizedName < ranked[j].item.NormalizedName
+		}
+		return ranked[i].item.FoodID < ranked[j].item.FoodID
+	})
+
+	result := make([]TacoFoodItem, 0, len(ranked))
+	for _, r := range ranked {
+		result = append(result, r.item)
+	}
+	return result
+}
+
+// tacoItemKey monta a chave do item no formato em que o DynamoDB devolve o
+// LastEvaluatedKey de uma query no GSI (chave da tabela + chave do índice).
+func tacoItemKey(item TacoFoodItem) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"food_id":         &types.AttributeValueMemberS{Value: item.FoodID},
+		"data_source":     &types.AttributeValueMemberS{Value: item.DataSource},
+		"normalized_name": &types.AttributeValueMemberS{Value: item.NormalizedName},
+	}
+}
+
+// paginateTacoItems devolve a página de itens ranqueados que começa logo após exclusiveStartKey.
+//
+// A paginação é simulada: a TACO é ranqueada por similaridade, ordem que o GSI não tem, então
+// cada página ranqueia de novo a partição inteira (em memória, ver
+// TacoView) e recomeça logo depois do food_id do cursor. Isso só é
+// coerente com a mesma busca, por isso o handler vincula o cursor ao termo e aos filtros
+// (CursorScope). Se o item do cursor saiu da lista (ex: a base ou os sinônimos mudaram entre
+// as páginas), o cursor é recusado com ErrInvalidCursor.
+func paginateTacoItems(ranked []TacoFoodItem, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	if limit <= 0 || limit > MaxSearchLimit {
+		limit = DefaultSearchLimit
+	}
+
+	start := 0
+	if len(exclusiveStartKey) > 0 {
+		startID, ok := exclusiveStartKey["food_id"].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		found := false
+		for i, item := range ranked {
+			if item.FoodID == startID.Value {
+				start = i + 1
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: item '%s' não faz parte desta busca", ErrInvalidCursor, startID.Value)
+		}
+	}
+
+	end := min(start+limit, len(ranked))
+	page := &SearchPage{Items: ranked[start:end]}
+	if end < len(ranked) {
+		page.LastEvaluatedKey = tacoItemKey(ranked[end-1])
+	}
+	return page, nil
+}
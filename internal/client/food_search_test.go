@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatchScore(t *testing.T) {
+	cases := []struct {
+		query string
+		name  string
+		want  bool
+	}{
+		{query: "feijão", name: "Feijao, carioca, cozido", want: true},
+		{query: "FEIJAO", name: "Feijão, carioca, cozido", want: true},
+		{query: "pão de açúcar", name: "Pao, de acucar", want: true},
+		{query: "arros", name: "Arroz, integral, cozido", want: true},
+		{query: "banan prta", name: "Banana, prata, crua", want: true},
+		{query: "brocolis", name: "Brócolis, cru", want: true},
+		{query: "cozido arroz", name: "Arroz, integral, cozido", want: true},
+		{query: "arr", name: "Arroz, integral, cozido", want: true},
+		{query: "aroz", name: "Arroz, integral, cozido", want: true},
+		{query: "ovo", name: "Uva, itália, crua", want: false},
+		{query: "arroz frito", name: "Arroz, integral, cozido", want: false},
+		{query: "", name: "Arroz, integral, cozido", want: false},
+	}
+	for _, c := range cases {
+		_, ok := matchScore(tokenize(c.query), c.name)
+		if ok != c.want {
+			t.Errorf("matchScore(%q, %q): ok = %t, esperava %t", c.query, c.name, ok, c.want)
+		}
+	}
+}
+
+func TestMatchScorePrefersExactAndShorterNames(t *testing.T) {
+	query := tokenize("arroz")
+	exact, _ := matchScore(query, "Arroz, cozido")
+	typo, _ := matchScore(tokenize("arros"), "Arroz, cozido")
+	longer, _ := matchScore(query, "Arroz, integral, cozido")
+	notFirst, _ := matchScore(query, "Biscoito, arroz")
+	if !(exact > typo) {
+		t.Errorf("correspondência exata (%g) deveria valer mais que com erro de digitação (%g)", exact, typo)
+	}
+	if !(exact > longer) {
+		t.Errorf("nome mais curto (%g) deveria valer mais que o mais longo (%g)", exact, longer)
+	}
+	if !(longer > notFirst) {
+		t.Errorf("nome que começa pelo termo (%g) deveria valer mais que o que não começa (%g)", longer, notFirst)
+	}
+}
+
+func TestRankTacoItems(t *testing.T) {
+	items := []TacoFoodItem{
+		{FoodID: "taco-4", NormalizedName: "biscoito arroz", OriginalName: "Biscoito, arroz"},
+		{FoodID: "taco-2", NormalizedName: "arroz integral cozido", OriginalName: "Arroz, integral, cozido"},
+		{FoodID: "taco-9", NormalizedName: "feijao carioca cozido", OriginalName: "Feijão, carioca, cozido"},
+		{FoodID: "taco-1", NormalizedName: "arroz cozido", OriginalName: "Arroz, cozido"},
+		{FoodID: "taco-3", NormalizedName: "arroz tipo 1 cozido", OriginalName: "Arroz, tipo 1, cozido"},
+	}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "arroz", want: []string{"taco-1", "taco-2", "taco-3", "taco-4"}},
+		{query: "Arróz", want: []string{"taco-1", "taco-2", "taco-3", "taco-4"}},
+		{query: "arros integal", want: []string{"taco-2"}},
+		{query: "feijão", want: []string{"taco-9"}},
+		{query: "macarrao", want: []string{}},
+	}
+	for _, c := range cases {
+		got := []string{}
+		for _, item := range rankTacoItems(c.query, nil, nil, items) {
+			got = append(got, item.FoodID)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("rankTacoItems(%q) = %v; esperava %v", c.query, got, c.want)
+		}
+	}
+}
+
+func TestRankTacoItemsSynonymsAndLocalizedNames(t *testing.T) {
+	items := []TacoFoodItem{
+		{FoodID: "taco-10", NormalizedName: "mandioca cozida", OriginalName: "Mandioca, cozida"},
+		{FoodID: "taco-11", NormalizedName: "macaxeira frita", OriginalName: "Macaxeira, frita"},
+	}
+
+	ranked := rankTacoItems("macaxeira", map[string][]string{"macaxeira": {"mandioca"}}, nil, items)
+	if len(ranked) != 2 || ranked[0].FoodID != "taco-11" || ranked[1].FoodID != "taco-10" {
+		t.Fatalf("correspondência direta deveria vir antes da por sinônimo: %+v", ranked)
+	}
+	if ranked[0].MatchedSynonym != "" || ranked[1].MatchedSynonym != "mandioca" {
+		t.Errorf("MatchedSynonym inesperado: %q, %q", ranked[0].MatchedSynonym, ranked[1].MatchedSynonym)
+	}
+
+	ranked = rankTacoItems("cassava", nil, map[string]string{"taco-10": "cassava boiled"}, items)
+	if len(ranked) != 1 || ranked[0].FoodID != "taco-10" {
+		t.Errorf("busca deveria usar o nome traduzido: %+v", ranked)
+	}
+}
+
+func TestTacoViewSharesOneLoad(t *testing.T) {
+	loads := 0
+	fail := false
+	view := NewTacoView(func(ctx context.Context) ([]TacoFoodItem, error) {
+		loads++
+		if fail {
+			return nil, errors.New("DynamoDB fora do ar")
+		}
+		return []TacoFoodItem{{FoodID: "taco-1", NormalizedName: "arroz cozido"}}, nil
+	})
+	index := NewNutrientIndex(view)
+
+	if _, err := view.Items(context.Background()); err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	if _, err := index.Foods(context.Background()); err != nil {
+		t.Fatalf("Foods: %v", err)
+	}
+	if loads != 1 {
+		t.Fatalf("busca e índice deveriam compartilhar uma carga, houve %d", loads)
+	}
+
+	fail = true
+	index.Invalidate()
+	items, err := view.Items(context.Background())
+	if err != nil || len(items) != 1 || loads != 2 {
+		t.Errorf("falha na recarga deveria manter a última cópia: %v, %v (cargas %d)", items, err, loads)
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"log"
+	"saas-nutri/internal/model"
+	"sync"
+	"time"
+)
+
+const DefaultTagOverrideTTL = time.Minute
+
+// FoodTagger aplica as correções manuais de tags sobre os alimentos. Mantém as correções em
+// memória e as relê a cada TTL, para que edições feitas em outra instância apareçam aqui.
+type FoodTagger struct {
+	repo TagOverrideRepository
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	overrides map[string]model.FoodTags
+	loadedAt  time.Time
+}
+
+func NewFoodTagger(repo TagOverrideRepository, ttl time.Duration) *FoodTagger {
+	if ttl <= 0 {
+		ttl = DefaultTagOverrideTTL
+	}
+	return &FoodTagger{repo: repo, ttl: ttl}
+}
+
+// Overrides devolve as correções indexadas por food_id. Se a leitura falhar, usa a última
+// versão carregada (ou nenhuma correção).
+func (t *FoodTagger) Overrides(ctx context.Context) map[string]model.FoodTags {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.overrides != nil && time.Since(t.loadedAt) < t.ttl {
+		return t.overrides
+	}
+
+	list, err := t.repo.ListTagOverrides(ctx)
+	if err != nil {
+		log.Printf("Erro ao carregar correções de tags, usando versão anterior: %v", err)
+		if t.overrides == nil {
+			return map[string]model.FoodTags{}
+		}
+		return t.overrides
+	}
+
+	overrides := make(map[string]model.FoodTags, len(list))
+	for _, o := range list {
+		overrides[o.FoodID] = o.tags()
+	}
+	t.overrides = overrides
+	t.loadedAt = time.Now()
+	return overrides
+}
+
+func (t *FoodTagger) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loadedAt = time.Time{}
+}
+
+// Apply troca as tags dos alimentos que têm correção manual.
+func (t *FoodTagger) Apply(ctx context.Context, foods ...*model.Food) {
+	overrides := t.Overrides(ctx)
+	if len(overrides) == 0 {
+		return
+	}
+	for _, food := range foods {
+		if tags, ok := overrides[food.Id]; ok {
+			food.Tags = &tags
+		}
+	}
+}
+
+func (t *FoodTagger) ListOverrides(ctx context.Context) ([]TagOverride, error) {
+	return t.repo.ListTagOverrides(ctx)
+}
+
+func (t *FoodTagger) PutOverride(ctx context.Context, override TagOverride) (*TagOverride, error) {
+	saved, err := t.repo.PutTagOverride(ctx, override)
+	if err == nil {
+		t.invalidate()
+	}
+	return saved, err
+}
+
+func (t *FoodTagger) DeleteOverride(ctx context.Context, foodID string) error {
+	err := t.repo.DeleteTagOverride(ctx, foodID)
+	if err == nil {
+		t.invalidate()
+	}
+	return err
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"saas-nutri/internal/model"
+	"sort"
+	"time"
+)
+
+const (
+	ValidationSeverityError   = "error"
+	ValidationSeverityWarning = "warning"
+)
+
+// Códigos das regras de validação.
+const (
+	IssueAtwaterMismatch = "atwater_mismatch"
+	IssueImpossibleValue = "impossible_value"
+	IssueMacrosOver100g  = "macros_over_100g"
+	IssueFiberOverCarbs  = "fiber_over_carbohydrate"
+	IssueFatBreakdown    = "fat_breakdown_over_total"
+	IssueMissingMeasures = "missing_measures"
+	IssueInvalidMeasure  = "invalid_measure"
+)
+
+const (
+	// Diferença tolerada entre a energia declarada e a calculada por Atwater (4/4/9): a maior
+	// entre a relativa e a absoluta. A TACO usa fatores específicos por alimento e o carboidrato
+	// inclui fibra, então pequenas diferenças são esperadas.
+	atwaterRelativeTolerance = 0.20
+	atwaterAbsoluteTolerance = 15.0
+
+	// roundingAllowanceG absorve o arredondamento dos valores publicados em uma casa decimal.
+	roundingAllowanceG = 0.5
+
+	// maxEnergyKcal é a energia de 100 g de gordura pura, o máximo físico por 100 g.
+	maxEnergyKcal = 900.0
+
+	// maxMeasureGrams limita medidas caseiras; acima disso é quase sempre erro de digitação.
+	maxMeasureGrams = 2000.0
+)
+
+var ErrInvalidFoodData = errors.New("dados do alimento inválidos")
+
+type ValidationIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+type FoodValidationResult struct {
+	FoodID   string            `json:"food_id"`
+	FoodName string            `json:"food_name"`
+	Issues   []ValidationIssue `json:"issues"`
+}
+
+// ValidationReport lista apenas os alimentos com algum problema, em ordem de food_id.
+type ValidationReport struct {
+	GeneratedAt       string                 `json:"generated_at"`
+	FoodsChecked      int                    `json:"foods_checked"`
+	FoodsWithErrors   int                    `json:"foods_with_errors"`
+	FoodsWithWarnings int                    `json:"foods_with_warnings"`
+	IssuesByCode      map[string]int         `json:"issues_by_code"`
+	Foods             []FoodValidationResult `json:"foods"`
+}
+
+func HasValidationErrors(issues []ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == ValidationSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationError junta os problemas bloqueantes em um erro que casa com ErrInvalidFoodData.
+func ValidationError(foodID string, issues []ValidationIssue) error {
+	for _, issue := range issues {
+		if issue.Severity == ValidationSeverityError {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidFoodData, foodID, issue.Message)
+		}
+	}
+	return nil
+}
+
+// validateMeasure aplica as regras de medida caseira. É a mesma regra usada na importação,
+// no relatório e na edição de medidas (via ValidateMeasure).
+func validateMeasure(m MeasureItem) []ValidationIssue {
+	field := "measures." + m.MeasureName
+	switch {
+	case math.IsNaN(m.GramEquivalent) || math.IsInf(m.GramEquivalent, 0) || m.GramEquivalent <= 0:
+		return []ValidationIssue{{Code: IssueInvalidMeasure, Severity: ValidationSeverityError, Field: field,
+			Message: fmt.Sprintf("medida '%s' com equivalente em gramas não positivo (%g)", m.MeasureName, m.GramEquivalent)}}
+	case m.GramEquivalent > maxMeasureGrams:
+		return []ValidationIssue{{Code: IssueInvalidMeasure, Severity: ValidationSeverityError, Field: field,
+			Message: fmt.Sprintf("medida '%s' com %g g, acima do máximo de %g g", m.MeasureName, m.GramEquivalent, maxMeasureGrams)}}
+	}
+	return nil
+}
+
+// ValidateFood aplica todas as regras a um alimento TACO. measures são as medidas caseiras
+// cadastradas; nil indica que as medidas não foram conferidas (ex: importação só de alimentos).
+func ValidateFood(item TacoFoodItem, measures []MeasureItem) []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(code, severity, field, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Code: code, Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	macros := []struct {
+		field string
+		value float64
+	}{
+		{NutrientKeyProtein, item.ProteinG},
+		{NutrientKeyCarbohydrate, item.CarbohydrateG},
+		{NutrientKeyFat, item.FatG},
+		{NutrientKeyFiber, item.FiberG},
+	}
+	impossible := false
+	for _, m := range macros {
+		if m.value < 0 || m.value > 100 || math.IsNaN(m.value) {
+			add(IssueImpossibleValue, ValidationSeverityError, m.field, "%s = %g g por 100 g está fora de 0 a 100", m.field, m.value)
+			impossible = true
+		}
+	}
+	if item.EnergyKcal < 0 || item.EnergyKcal > maxEnergyKcal || math.IsNaN(item.EnergyKcal) {
+		add(IssueImpossibleValue, ValidationSeverityError, NutrientKeyEnergy, "energia de %g kcal por 100 g está fora de 0 a %g", item.EnergyKcal, maxEnergyKcal)
+		impossible = true
+	}
+
+	for _, def := range model.NutrientDefinitions {
+		n, ok := item.Nutrients[def.Key]
+		if !ok || n.Value == nil {
+			continue
+		}
+		if limit := nutrientLimitPer100g(def.Unit); *n.Value < 0 || *n.Value > limit {
+			add(IssueImpossibleValue, ValidationSeverityError, def.Key, "%s = %g %s por 100 g está fora de 0 a %g", def.Key, *n.Value, def.Unit, limit)
+		}
+	}
+
+	// Na TACO o carboidrato é total (inclui a fibra), então fibra é parte dele.
+	if total := item.ProteinG + item.CarbohydrateG + item.FatG; total > 100+roundingAllowanceG {
+		add(IssueMacrosOver100g, ValidationSeverityError, "", "proteína + carboidrato + lipídios somam %.1f g em 100 g", total)
+	}
+	if item.FiberG > item.CarbohydrateG+roundingAllowanceG {
+		add(IssueFiberOverCarbs, ValidationSeverityWarning, NutrientKeyFiber, "fibra (%.1f g) maior que o carboidrato total (%.1f g)", item.FiberG, item.CarbohydrateG)
+	}
+	if fats := fatBreakdown(item.Nutrients); fats > item.FatG+roundingAllowanceG {
+		add(IssueFatBreakdown, ValidationSeverityWarning, NutrientKeyFat, "ácidos graxos somam %.1f g, acima dos lipídios totais (%.1f g)", fats, item.FatG)
+	}
+
+	if !impossible {
+		atwater := 4*item.ProteinG + 4*item.CarbohydrateG + 9*item.FatG
+		diff := math.Abs(item.EnergyKcal - atwater)
+		if diff > math.Max(atwaterAbsoluteTolerance, atwaterRelativeTolerance*atwater) {
+			add(IssueAtwaterMismatch, ValidationSeverityWarning, NutrientKeyEnergy, "energia declarada de %.0f kcal difere da calculada por Atwater (%.0f kcal)", item.EnergyKcal, atwater)
+		}
+	}
+
+	if measures != nil {
+		household := 0
+		for _, m := range measures {
+			if normalizeString(m.MeasureName) == defaultMeasureName {
+				continue
+			}
+			household++
+			issues = append(issues, validateMeasure(m)...)
+		}
+		if household == 0 {
+			add(IssueMissingMeasures, ValidationSeverityWarning, "measures", "alimento sem medidas caseiras além de grama")
+		}
+	}
+
+	return issues
+}
+
+func nutrientLimitPer100g(unit string) float64 {
+	switch unit {
+	case "mg":
+		return 100 * 1000
+	case "mcg":
+		return 100 * 1000 * 1000
+	}
+	return 100
+}
+
+func fatBreakdown(nutrients map[string]TacoNutrient) float64 {
+	total := 0.0
+	for _, key := range []string{model.NutrientSaturatedFat, model.NutrientMonounsaturatedFat, model.NutrientPolyunsaturatedFat} {
+		if n, ok := nutrients[key]; ok && n.Value != nil {
+			total += *n.Value
+		}
+	}
+	return total
+}
+
+// ValidateFoods gera o relatório de um conjunto de alimentos. measures é indexado por food_id;
+// com measures nil, a regra de medidas ausentes não é aplicada.
+func ValidateFoods(foods []TacoFoodItem, measures map[string][]MeasureItem) ValidationReport {
+	report := ValidationReport{
+		GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
+		FoodsChecked: len(foods),
+		IssuesByCode: map[string]int{},
+		Foods:        []FoodValidationResult{},
+	}
+	for _, item := range foods {
+		var foodMeasures []MeasureItem
+		if measures != nil {
+			foodMeasures = append([]MeasureItem{}, measures[item.FoodID]...)
+		}
+		issues := ValidateFood(item, foodMeasures)
+		if len(issues) == 0 {
+			continue
+		}
+		for _, issue := range issues {
+			report.IssuesByCode[issue.Code]++
+		}
+		if HasValidationErrors(issues) {
+			report.FoodsWithErrors++
+		} else {
+			report.FoodsWithWarnings++
+		}
+		report.Foods = append(report.Foods, FoodValidationResult{FoodID: item.FoodID, FoodName: item.OriginalName, Issues: issues})
+	}
+	sort.Slice(report.Foods, func(i, j int) bool { return report.Foods[i].FoodID < report.Foods[j].FoodID })
+	return report
+}
+
+// ValidateRepository valida todos os alimentos TACO do repositório com suas medidas caseiras.
+func ValidateRepository(ctx context.Context, repo FoodRepository) (*ValidationReport, error) {
+	foods, err := repo.ListFoods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]TacoFoodItem, len(foods))
+	for _, item := range foods {
+		items[item.FoodID] = item
+	}
+	loaded, failures := loadMeasuresConcurrently(ctx, items, repo.GetMeasuresForFood)
+	if err := firstFailure(failures); err != nil {
+		return nil, err
+	}
+
+	measures := make(map[string][]MeasureItem, len(loaded))
+	for id, food := range loaded {
+		for _, m := range food.HouseholdMeasures {
+			measures[id] = append(measures[id], MeasureItem{FoodID: id, MeasureName: m.Name, GramEquivalent: m.Grams})
+		}
+	}
+	report := ValidateFoods(foods, measures)
+	return &report, nil
+}
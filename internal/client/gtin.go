@@ -0,0 +1,44 @@
+package client
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidGTIN = errors.New("código de barras inválido")
+
+// NormalizeGTIN valida um código EAN/GTIN (8, 12, 13 ou 14 dígitos), incluindo o
+// dígito verificador, e devolve o código sem espaços.
+func NormalizeGTIN(code string) (string, error) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return "", ErrInvalidGTIN
+	}
+
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return "", ErrInvalidGTIN
+		}
+		digit := int(c - '0')
+		// A partir da direita (sem o verificador), as posições alternam peso 3 e 1.
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	last := code[len(code)-1]
+	if last < '0' || last > '9' {
+		return "", ErrInvalidGTIN
+	}
+	if (10-sum%10)%10 != int(last-'0') {
+		return "", ErrInvalidGTIN
+	}
+
+	return code, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"saas-nutri/internal/model"
+	"strings"
+)
+
+var (
+	ErrMeasureExists   = errors.New("medida caseira já cadastrada para este alimento")
+	ErrMeasureNotFound = errors.New("medida caseira não encontrada")
+	ErrInvalidMeasure  = errors.New("medida caseira inválida")
+)
+
+// defaultMeasureName é a medida "grama", sempre devolvida e que não pode ser editada.
+const defaultMeasureName = "grama"
+
+func ValidateMeasure(m MeasureItem) error {
+	if strings.TrimSpace(m.MeasureName) == "" {
+		return fmt.Errorf("%w: nome da medida é obrigatório", ErrInvalidMeasure)
+	}
+	// Mesmas regras do relatório de qualidade e da importação.
+	for _, issue := range validateMeasure(m) {
+		if issue.Severity == ValidationSeverityError {
+			return fmt.Errorf("%w: %s", ErrInvalidMeasure, issue.Message)
+		}
+	}
+	return nil
+}
+
+// IsDefault compara a chave da medida, que fica em português, e não o nome de exibição.
+func (m MeasureItem) IsDefault() bool {
+	return normalizeString(m.MeasureName) == defaultMeasureName
+}
+
+// HouseholdMeasure converte a medida no formato devolvido junto do alimento.
+func (m MeasureItem) HouseholdMeasure() model.HouseholdMeasure {
+	return model.HouseholdMeasure{Name: m.DisplayName, Grams: m.GramEquivalent, IsDefault: m.IsDefault(), Key: m.MeasureName}
+}
+
+func measureDisplayName(m MeasureItem) string {
+	if m.MeasureQuantity != "" {
+		return m.MeasureQuantity + " " + m.MeasureName
+	}
+	return m.MeasureName
+}
+
+// checkDuplicateMeasure compara nomes normalizados para que "Colher de sopa" e
+// "colher de sopa" não virem duas medidas diferentes.
+func checkDuplicateMeasure(existing []MeasureItem, name, currentName string) error {
+	normalized := normalizeString(name)
+	if normalized == defaultMeasureName || normalizeString(currentName) == defaultMeasureName {
+		return fmt.Errorf("%w: a medida '%s' é padrão e não pode ser alterada", ErrMeasureExists, defaultMeasureName)
+	}
+	for _, m := range existing {
+		if m.MeasureName == currentName {
+			continue
+		}
+		if normalizeString(m.MeasureName) == normalized {
+			return fmt.Errorf("%w: %s", ErrMeasureExists, m.MeasureName)
+		}
+	}
+	return nil
+}
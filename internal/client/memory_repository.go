@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"saas-nutri/internal/model"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type InMemoryFoodRepository struct {
+	mu       sync.RWMutex
+	foods    map[string]TacoFoodItem
+	measures map[string][]MeasureItem
+}
+
+func NewInMemoryFoodRepository(foods []TacoFoodItem, measures []MeasureItem) *InMemoryFoodRepository {
+	repo := &InMemoryFoodRepository{
+		foods:    make(map[string]TacoFoodItem),
+		measures: make(map[string][]MeasureItem),
+	}
+	for _, f := range foods {
+		repo.AddFood(f)
+	}
+	for _, m := range measures {
+		repo.AddMeasure(m)
+	}
+	return repo
+}
+
+func (r *InMemoryFoodRepository) AddFood(item TacoFoodItem) {
+	if item.NormalizedName == "" {
+		item.NormalizedName = normalizeString(item.OriginalName)
+	}
+	if item.DataSource == "" {
+		item.DataSource = "TACO"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.foods[item.FoodID] = item
+}
+
+func (r *InMemoryFoodRepository) AddMeasure(item MeasureItem) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.measures[item.FoodID] = append(r.measures[item.FoodID], item)
+}
+
+func (r *InMemoryFoodRepository) SearchFoodsByNamePrefix(ctx context.Context, namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	normalizedQuery := normalizeString(namePrefix)
+
+	if normalizedQuery == "" {
+		return &SearchPage{}, nil
+	}
+
+	r.mu.RLock()
+	candidates := make([]TacoFoodItem, 0, len(r.foods))
+	for _, f := range r.foods {
+		if f.DataSource == "TACO" {
+			candidates = append(candidates, f)
+		}
+	}
+	r.mu.RUnlock()
+
+	page, err := paginateTacoItems(rankTacoItems(normalizedQuery, filter.Synonyms, filter.localizedNames(), filter.filterTacoItems(candidates)), limit, exclusiveStartKey)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Busca em memória retornou %d itens para a busca: '%s'", len(page.Items), normalizedQuery)
+
+	return page, nil
+}
+
+func (r *InMemoryFoodRepository) ListFoods(ctx context.Context) ([]TacoFoodItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]TacoFoodItem, 0, len(r.foods))
+	for _, f := range r.foods {
+		if f.DataSource == "TACO" {
+			items = append(items, f)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].FoodID < items[j].FoodID })
+	return items, nil
+}
+
+func (r *InMemoryFoodRepository) ListFoodsPage(ctx context.Context, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	items, err := r.ListFoods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return paginateTacoItems(items, limit, exclusiveStartKey)
+}
+
+func (r *InMemoryFoodRepository) GetMeasuresForFood(ctx context.Context, foodID string) ([]MeasureItem, error) {
+	items := []MeasureItem{{
+		MeasureName:    "grama",
+		DisplayName:    "Grama",
+		GramEquivalent: 1.0,
+	}}
+
+	r.mu.RLock()
+	stored := r.measures[foodID]
+	r.mu.RUnlock()
+
+	for _, m := range stored {
+		m.DisplayName = measureDisplayName(m)
+		items = append(items, m)
+	}
+
+	return items, nil
+}
+
+func (r *InMemoryFoodRepository) GetFoodWithMeasures(ctx context.Context, foodID string) (*model.Food, error) {
+	r.mu.RLock()
+	foodItem, ok := r.foods[foodID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+
+	measures, err := r.GetMeasuresForFood(ctx, foodID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar medidas caseiras: %w", err)
+	}
+
+	return foodWithMeasures(foodItem, measures), nil
+}
+
+func (r *InMemoryFoodRepository) GetFoodsWithMeasures(ctx context.Context, foodIDs []string) (map[string]*model.Food, map[string]error, error) {
+	r.mu.RLock()
+	items := make(map[string]TacoFoodItem, len(foodIDs))
+	for _, id := range foodIDs {
+		if item, ok := r.foods[id]; ok {
+			items[id] = item
+		}
+	}
+	r.mu.RUnlock()
+
+	foods, failures := loadMeasuresConcurrently(ctx, items, r.GetMeasuresForFood)
+	return foods, failures, nil
+}
+
+func (r *InMemoryFoodRepository) CreateMeasure(ctx context.Context, measure MeasureItem) (*MeasureItem, error) {
+	if err := ValidateMeasure(measure); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.foods[measure.FoodID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, measure.FoodID)
+	}
+	if err := checkDuplicateMeasure(r.measures[measure.FoodID], measure.MeasureName, ""); err != nil {
+		return nil, err
+	}
+
+	measure.DisplayName = ""
+	r.measures[measure.FoodID] = append(r.measures[measure.FoodID], measure)
+
+	measure.DisplayName = measureDisplayName(measure)
+	return &measure, nil
+}
+
+func (r *InMemoryFoodRepository) UpdateMeasure(ctx context.Context, foodID, measureName string, measure MeasureItem) (*MeasureItem, error) {
+	measure.FoodID = foodID
+	if measure.MeasureName == "" {
+		measure.MeasureName = measureName
+	}
+	if err := ValidateMeasure(measure); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.foods[foodID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	stored := r.measures[foodID]
+	if err := checkDuplicateMeasure(stored, measure.MeasureName, measureName); err != nil {
+		return nil, err
+	}
+
+	for i, m := range stored {
+		if m.MeasureName == measureName {
+			measure.DisplayName = ""
+			stored[i] = measure
+			measure.DisplayName = measureDisplayName(measure)
+			return &measure, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrMeasureNotFound, measureName)
+}
+
+func (r *InMemoryFoodRepository) DeleteMeasure(ctx context.Context, foodID, measureName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.measures[foodID]
+	for i, m := range stored {
+		if m.MeasureName == measureName {
+			r.measures[foodID] = append(stored[:i:i], stored[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrMeasureNotFound, measureName)
+}
+
+func SampleTacoFoods() ([]TacoFoodItem, []MeasureItem) {
+	foods := []TacoFoodItem{
+		{FoodID: "taco-1", FoodGroup: "cereais", OriginalName: "Arroz, integral, cozido", EnergyKcal: 124, ProteinG: 2.6, CarbohydrateG: 25.8, FatG: 1.0, FiberG: 2.7},
+		{FoodID: "taco-3", FoodGroup: "cereais", OriginalName: "Arroz, tipo 1, cozido", EnergyKcal: 128, ProteinG: 2.5, CarbohydrateG: 28.1, FatG: 0.2, FiberG: 1.6},
+		{FoodID: "taco-182", FoodGroup: "frutas", OriginalName: "Maçã, Fuji, com casca, crua", EnergyKcal: 56, ProteinG: 0.3, CarbohydrateG: 15.2, FatG: 0.0, FiberG: 1.3},
+		{FoodID: "taco-561", FoodGroup: "leguminosas", OriginalName: "Feijão, carioca, cozido", EnergyKcal: 76, ProteinG: 4.8, CarbohydrateG: 13.6, FatG: 0.5, FiberG: 8.5},
+		{FoodID: "taco-567", FoodGroup: "leguminosas", OriginalName: "Feijão, preto, cozido", EnergyKcal: 77, ProteinG: 4.5, CarbohydrateG: 14.0, FatG: 0.5, FiberG: 8.4},
+	}
+	measures := []MeasureItem{
+		{FoodID: "taco-3", MeasureName: "colher de sopa cheia", MeasureQuantity: "1", GramEquivalent: 25},
+		{FoodID: "taco-182", MeasureName: "unidade média", MeasureQuantity: "1", GramEquivalent: 130},
+		{FoodID: "taco-561", MeasureName: "concha média", MeasureQuantity: "1", GramEquivalent: 86},
+		{FoodID: "taco-567", MeasureName: "concha média", MeasureQuantity: "1", GramEquivalent: 86},
+	}
+	return foods, measures
+}
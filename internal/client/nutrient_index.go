@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"saas-nutri/internal/model"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrInvalidNutrientQuery = errors.New("consulta por nutrientes inválida")
+
+// Campos de macronutrientes aceitos em filtros e ordenação, além das chaves de
+// model.NutrientDefinitions.
+const (
+	NutrientKeyEnergy       = "energy_kcal"
+	NutrientKeyProtein      = "protein_g"
+	NutrientKeyCarbohydrate = "carbohydrate_g"
+	NutrientKeyFat          = "fat_g"
+	NutrientKeyFiber        = "fiber_g"
+)
+
+// NutrientRange filtra por um nutriente por 100 g. Limites nulos não filtram.
+type NutrientRange struct {
+	Key string
+	Min *float64
+	Max *float64
+}
+
+type NutrientQuery struct {
+	Ranges []NutrientRange
+	Filter SearchFilter
+	// SortBy é a chave do nutriente usada na ordenação. Com Density, ordena pelo valor
+	// por 100 kcal em vez de por 100 g.
+	SortBy            string
+	Density           bool
+	Ascending         bool
+	Limit             int
+	ExclusiveStartKey map[string]types.AttributeValue
+}
+
+// IsNutrientQueryKey informa se a chave pode ser usada em filtros e ordenação.
+func IsNutrientQueryKey(key string) bool {
+	switch key {
+	case NutrientKeyEnergy, NutrientKeyProtein, NutrientKeyCarbohydrate, NutrientKeyFat, NutrientKeyFiber:
+		return true
+	}
+	return model.NutrientUnit(key) != ""
+}
+
+// nutrientQueryValue devolve o valor por 100 g. Traço conta como zero; nutriente não
+// analisado não tem valor e o alimento fica fora de filtros e ordenação por ele.
+func nutrientQueryValue(item TacoFoodItem, key string) (float64, bool) {
+	switch key {
+	case NutrientKeyEnergy:
+		return item.EnergyKcal, true
+	case NutrientKeyProtein:
+		return item.ProteinG, true
+	case NutrientKeyCarbohydrate:
+		return item.CarbohydrateG, true
+	case NutrientKeyFat:
+		return item.FatG, true
+	case NutrientKeyFiber:
+		return item.FiberG, true
+	}
+	nutrient, ok := item.Nutrients[key]
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case nutrient.Value != nil:
+		return *nutrient.Value, true
+	case nutrient.Status == model.NutrientStatusTrace:
+		return 0, true
+	}
+	return 0, false
+}
+
+func (q NutrientQuery) validate() error {
+	for _, rg := range q.Ranges {
+		if !IsNutrientQueryKey(rg.Key) {
+			return fmt.Errorf("%w: nutriente '%s' desconhecido", ErrInvalidNutrientQuery, rg.Key)
+		}
+		if rg.Min != nil && rg.Max != nil && *rg.Min > *rg.Max {
+			return fmt.Errorf("%w: mínimo maior que o máximo para '%s'", ErrInvalidNutrientQuery, rg.Key)
+		}
+	}
+	if q.SortBy != "" && !IsNutrientQueryKey(q.SortBy) {
+		return fmt.Errorf("%w: nutriente '%s' desconhecido", ErrInvalidNutrientQuery, q.SortBy)
+	}
+	if q.Density && (q.SortBy == "" || q.SortBy == NutrientKeyEnergy) {
+		return fmt.Errorf("%w: densidade exige ordenação por um nutriente diferente de energia", ErrInvalidNutrientQuery)
+	}
+	return nil
+}
+
+func (q NutrientQuery) matches(item TacoFoodItem) bool {
+	if !q.Filter.matchesTacoItem(item) {
+		return false
+	}
+	for _, rg := range q.Ranges {
+		value, ok := nutrientQueryValue(item, rg.Key)
+		if !ok {
+			return false
+		}
+		if (rg.Min != nil && value < *rg.Min) || (rg.Max != nil && value > *rg.Max) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q NutrientQuery) sortValue(item TacoFoodItem) (float64, bool) {
+	value, ok := nutrientQueryValue(item, q.SortBy)
+	if !ok {
+		return 0, false
+	}
+	if !q.Density {
+		return value, true
+	}
+	if item.EnergyKcal <= 0 {
+		return 0, false
+	}
+	return value / item.EnergyKcal * 100, true
+}
+
+// NutrientIndex responde consultas por faixa de nutrientes sobre a TacoView, a mesma cópia
+// em memória da partição TACO usada pela busca.
+//
+// A visão deve ler do repositório principal, e não do FallbackFoodRepository: a reserva é só
+// uma amostra e, lida pela visão, passaria por uma carga boa e ficaria guardada por um TTL
+// inteiro. Fallback, se definido, responde enquanto a visão nunca carregou do principal, sem
+// que o resultado fique guardado.
+type NutrientIndex struct {
+	view     *TacoView
+	Fallback FoodRepository
+}
+
+func NewNutrientIndex(view *TacoView) *NutrientIndex {
+	return &NutrientIndex{view: view}
+}
+
+// Invalidate força a recarga da visão na próxima consulta.
+func (idx *NutrientIndex) Invalidate() {
+	idx.view.Invalidate()
+}
+
+func (idx *NutrientIndex) snapshot(ctx context.Context) ([]TacoFoodItem, error) {
+	items, err := idx.view.Items(ctx)
+	if err != nil {
+		if idx.Fallback != nil && !isBusinessError(err) {
+			log.Printf("Erro ao carregar índice de nutrientes, respondendo com o repositório reserva: %v", err)
+			return idx.Fallback.ListFoods(ctx)
+		}
+		return nil, fmt.Errorf("erro ao carregar índice de nutrientes: %w", err)
+	}
+	return items, nil
+}
+
+// Query filtra e ordena o índice e devolve a página que começa após q.ExclusiveStartKey.
+// Sem SortBy, os resultados saem em ordem de food_id.
+func (idx *NutrientIndex) Query(ctx context.Context, q NutrientQuery) (*SearchPage, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	items, err := idx.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		item  TacoFoodItem
+		value float64
+	}
+	matched := make([]scored, 0, len(items))
+	for _, item := range items {
+		if !q.matches(item) {
+			continue
+		}
+		var value float64
+		if q.SortBy != "" {
+			v, ok := q.sortValue(item)
+			if !ok {
+				continue
+			}
+			value = v
+		}
+		matched = append(matched, scored{item: item, value: value})
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].value != matched[j].value {
+			if q.Ascending {
+				return matched[i].value < matched[j].value
+			}
+			return matched[i].value > matched[j].value
+		}
+		return matched[i].item.FoodID < matched[j].item.FoodID
+	})
+
+	ordered := make([]TacoFoodItem, len(matched))
+	for i, m := range matched {
+		ordered[i] = m.item
+	}
+	return paginateTacoItems(ordered, q.Limit, q.ExclusiveStartKey)
+}
+
+// Foods devolve todos os alimentos do índice, recarregando-o se necessário.
+func (idx *NutrientIndex) Foods(ctx context.Context) ([]model.Food, error) {
+	items, err := idx.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	foods := make([]model.Food, 0, len(items))
+	for _, item := range items {
+		foods = append(foods, MapTacoToFood(item))
+	}
+	return foods, nil
+}
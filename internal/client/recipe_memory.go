@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"saas-nutri/internal/model"
+	"sort"
+	"sync"
+)
+
+type InMemoryRecipeRepository struct {
+	mu    sync.RWMutex
+	items map[string]map[string]RecipeItem
+}
+
+func NewInMemoryRecipeRepository() *InMemoryRecipeRepository {
+	return &InMemoryRecipeRepository{items: make(map[string]map[string]RecipeItem)}
+}
+
+func (r *InMemoryRecipeRepository) CreateRecipe(ctx context.Context, tenantID string, recipe model.Recipe, snapshot model.Food) (*model.Recipe, error) {
+	recipeID, err := newRecipeID()
+	if err != nil {
+		return nil, err
+	}
+	item := newRecipeItem(tenantID, recipeID, recipe, snapshot)
+
+	r.mu.Lock()
+	if r.items[tenantID] == nil {
+		r.items[tenantID] = make(map[string]RecipeItem)
+	}
+	r.items[tenantID][recipeID] = item
+	r.mu.Unlock()
+
+	created := item.toRecipe()
+	return &created, nil
+}
+
+func (r *InMemoryRecipeRepository) GetRecipe(ctx context.Context, tenantID, recipeID string) (*model.Recipe, error) {
+	r.mu.RLock()
+	item, ok := r.items[tenantID][recipeID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, recipeID)
+	}
+	recipe := item.toRecipe()
+	return &recipe, nil
+}
+
+func (r *InMemoryRecipeRepository) tenantItems(tenantID string) []RecipeItem {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]RecipeItem, 0, len(r.items[tenantID]))
+	for _, item := range r.items[tenantID] {
+		items = append(items, item)
+	}
+	return items
+}
+
+func (r *InMemoryRecipeRepository) ListRecipes(ctx context.Context, tenantID string) ([]model.Recipe, error) {
+	items := r.tenantItems(tenantID)
+	sort.Slice(items, func(i, j int) bool { return items[i].NormalizedName < items[j].NormalizedName })
+
+	recipes := make([]model.Recipe, 0, len(items))
+	for _, item := range items {
+		recipes = append(recipes, item.toRecipe())
+	}
+	return recipes, nil
+}
+
+func (r *InMemoryRecipeRepository) UpdateRecipe(ctx context.Context, tenantID, recipeID string, recipe model.Recipe, snapshot model.Food) (*model.Recipe, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[tenantID][recipeID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, recipeID)
+	}
+	item := newRecipeItem(tenantID, recipeID, recipe, snapshot)
+	r.items[tenantID][recipeID] = item
+
+	updated := item.toRecipe()
+	return &updated, nil
+}
+
+func (r *InMemoryRecipeRepository) DeleteRecipe(ctx context.Context, tenantID, recipeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[tenantID][recipeID]; !ok {
+		return fmt.Errorf("%w: %s", ErrFoodNotFound, recipeID)
+	}
+	delete(r.items[tenantID], recipeID)
+	return nil
+}
+
+func (r *InMemoryRecipeRepository) SearchRecipes(ctx context.Context, tenantID, query string, limit int) ([]model.Food, error) {
+	if normalizeString(query) == "" {
+		return []model.Food{}, nil
+	}
+	return rankRecipes(query, r.tenantItems(tenantID), limit), nil
+}
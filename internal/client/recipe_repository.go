@@ -0,0 +1,331 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/nutrition"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const recipeIDPrefix = "recipe-"
+
+var ErrInvalidRecipe = errors.New("receita inválida")
+
+// RecipeRepository guarda as receitas de cada tenant. Junto da receita fica uma cópia dos
+// nutrientes calculados (por 100 g), usada nas buscas para não recalcular cada resultado.
+type RecipeRepository interface {
+	CreateRecipe(ctx context.Context, tenantID string, recipe model.Recipe, snapshot model.Food) (*model.Recipe, error)
+	GetRecipe(ctx context.Context, tenantID, recipeID string) (*model.Recipe, error)
+	ListRecipes(ctx context.Context, tenantID string) ([]model.Recipe, error)
+	UpdateRecipe(ctx context.Context, tenantID, recipeID string, recipe model.Recipe, snapshot model.Food) (*model.Recipe, error)
+	DeleteRecipe(ctx context.Context, tenantID, recipeID string) error
+	SearchRecipes(ctx context.Context, tenantID, query string, limit int) ([]model.Food, error)
+}
+
+// RecipeItem é o item da tabela Recipes (chave: tenant_id + recipe_id).
+type RecipeItem struct {
+	TenantID       string                 `dynamodbav:"tenant_id"`
+	RecipeID       string                 `dynamodbav:"recipe_id"`
+	Name           string                 `dynamodbav:"name"`
+	NormalizedName string                 `dynamodbav:"normalized_name"`
+	Ingredients    []RecipeIngredientItem `dynamodbav:"ingredients"`
+	YieldGrams     float64                `dynamodbav:"yield_grams"`
+	Servings       float64                `dynamodbav:"servings"`
+	Snapshot       CustomFoodItem         `dynamodbav:"snapshot"`
+	UpdatedAt      string                 `dynamodbav:"updated_at"`
+}
+
+type RecipeIngredientItem struct {
+	FoodID      string  `dynamodbav:"food_id"`
+	MeasureName string  `dynamodbav:"measure_name,omitempty"`
+	Grams       float64 `dynamodbav:"grams,omitempty"`
+	Quantity    float64 `dynamodbav:"quantity"`
+}
+
+func IsRecipeID(foodID string) bool {
+	return strings.HasPrefix(foodID, recipeIDPrefix)
+}
+
+func newRecipeID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("erro ao gerar id da receita: %w", err)
+	}
+	return recipeIDPrefix + hex.EncodeToString(b), nil
+}
+
+// NormalizeRecipe aplica os valores padrão (quantidade 1, uma porção) e valida a receita.
+func NormalizeRecipe(recipe model.Recipe) (model.Recipe, error) {
+	recipe.Name = strings.TrimSpace(recipe.Name)
+	if recipe.Name == "" {
+		return recipe, fmt.Errorf("%w: nome é obrigatório", ErrInvalidRecipe)
+	}
+	if len(recipe.Ingredients) == 0 {
+		return recipe, fmt.Errorf("%w: informe ao menos um ingrediente", ErrInvalidRecipe)
+	}
+	if recipe.Servings == 0 {
+		recipe.Servings = 1
+	}
+	if recipe.Servings < 0 || math.IsNaN(recipe.Servings) {
+		return recipe, fmt.Errorf("%w: servings deve ser positivo", ErrInvalidRecipe)
+	}
+	if recipe.YieldGrams < 0 || math.IsNaN(recipe.YieldGrams) {
+		return recipe, fmt.Errorf("%w: yield_grams não pode ser negativo", ErrInvalidRecipe)
+	}
+
+	ingredients := make([]model.RecipeIngredient, 0, len(recipe.Ingredients))
+	for i, ing := range recipe.Ingredients {
+		ing.FoodID = strings.TrimSpace(ing.FoodID)
+		ing.MeasureName = strings.TrimSpace(ing.MeasureName)
+		if ing.FoodID == "" {
+			return recipe, fmt.Errorf("%w: ingrediente %d sem food_id", ErrInvalidRecipe, i+1)
+		}
+		if (ing.MeasureName == "") == (ing.Grams == 0) {
+			return recipe, fmt.Errorf("%w: ingrediente %d deve informar measure_name ou grams (apenas um)", ErrInvalidRecipe, i+1)
+		}
+		if ing.Grams < 0 {
+			return recipe, fmt.Errorf("%w: ingrediente %d com grams negativo", ErrInvalidRecipe, i+1)
+		}
+		if ing.Quantity == 0 {
+			ing.Quantity = 1
+		}
+		if ing.Quantity < 0 {
+			return recipe, fmt.Errorf("%w: ingrediente %d com quantity negativa", ErrInvalidRecipe, i+1)
+		}
+		ingredients = append(ingredients, ing)
+	}
+	recipe.Ingredients = ingredients
+
+	return recipe, nil
+}
+
+func newRecipeItem(tenantID, recipeID string, recipe model.Recipe, snapshot model.Food) RecipeItem {
+	snapshotItem := newCustomFoodItem(tenantID, recipeID, snapshot)
+	snapshotItem.HouseholdMeasures = nil
+	for _, m := range snapshot.HouseholdMeasures {
+		if !m.IsDefault {
+			snapshotItem.HouseholdMeasures = append(snapshotItem.HouseholdMeasures, CustomMeasure{Name: m.Name, Grams: m.Grams})
+		}
+	}
+
+	ingredients := make([]RecipeIngredientItem, 0, len(recipe.Ingredients))
+	for _, ing := range recipe.Ingredients {
+		ingredients = append(ingredients, RecipeIngredientItem(ing))
+	}
+
+	return RecipeItem{
+		TenantID:       tenantID,
+		RecipeID:       recipeID,
+		Name:           recipe.Name,
+		NormalizedName: normalizeString(recipe.Name),
+		Ingredients:    ingredients,
+		YieldGrams:     recipe.YieldGrams,
+		Servings:       recipe.Servings,
+		Snapshot:       snapshotItem,
+		UpdatedAt:      time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (i RecipeItem) toRecipe() model.Recipe {
+	ingredients := make([]model.RecipeIngredient, 0, len(i.Ingredients))
+	for _, ing := range i.Ingredients {
+		ingredients = append(ingredients, model.RecipeIngredient(ing))
+	}
+
+	return model.Recipe{
+		Id:          i.RecipeID,
+		Name:        i.Name,
+		Ingredients: ingredients,
+		YieldGrams:  i.YieldGrams,
+		Servings:    i.Servings,
+	}
+}
+
+func (i RecipeItem) snapshotFood() model.Food {
+	food := i.Snapshot.toFood()
+	food.Id = i.RecipeID
+	food.Name = i.Name
+	food.Source = nutrition.SourceRecipe
+	return food
+}
+
+func rankRecipes(query string, items []RecipeItem, limit int) []model.Food {
+	snapshots := make([]CustomFoodItem, 0, len(items))
+	byID := make(map[string]RecipeItem, len(items))
+	for _, item := range items {
+		snapshot := item.Snapshot
+		snapshot.FoodID = item.RecipeID
+		snapshot.Name = item.Name
+		snapshot.NormalizedName = item.NormalizedName
+		snapshots = append(snapshots, snapshot)
+		byID[item.RecipeID] = item
+	}
+
+	ranked := rankCustomFoods(query, snapshots, limit)
+	for i := range ranked {
+		ranked[i] = byID[ranked[i].Id].snapshotFood()
+	}
+	return ranked
+}
+
+type DynamoRecipeRepository struct {
+	DB        *dynamodb.Client
+	TableName string
+}
+
+func NewDynamoRecipeRepository(db *dynamodb.Client, tableName string) *DynamoRecipeRepository {
+	return &DynamoRecipeRepository{DB: db, TableName: tableName}
+}
+
+func recipeKey(tenantID, recipeID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		"recipe_id": &types.AttributeValueMemberS{Value: recipeID},
+	}
+}
+
+func (r *DynamoRecipeRepository) putRecipe(ctx context.Context, item RecipeItem, condition string) error {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar receita: %w", err)
+	}
+
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                av,
+		ConditionExpression: aws.String(condition),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s", ErrFoodNotFound, item.RecipeID)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao gravar receita no DynamoDB: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoRecipeRepository) CreateRecipe(ctx context.Context, tenantID string, recipe model.Recipe, snapshot model.Food) (*model.Recipe, error) {
+	recipeID, err := newRecipeID()
+	if err != nil {
+		return nil, err
+	}
+
+	item := newRecipeItem(tenantID, recipeID, recipe, snapshot)
+	if err := r.putRecipe(ctx, item, "attribute_not_exists(recipe_id)"); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Receita %s criada para o tenant %s", recipeID, tenantID)
+	created := item.toRecipe()
+	return &created, nil
+}
+
+func (r *DynamoRecipeRepository) GetRecipe(ctx context.Context, tenantID, recipeID string) (*model.Recipe, error) {
+	result, err := r.DB.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       recipeKey(tenantID, recipeID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar receita no DynamoDB: %w", err)
+	}
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, recipeID)
+	}
+
+	var item RecipeItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar receita: %w", err)
+	}
+	recipe := item.toRecipe()
+	return &recipe, nil
+}
+
+func (r *DynamoRecipeRepository) queryTenant(ctx context.Context, tenantID string) ([]RecipeItem, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.TableName),
+		KeyConditionExpression: aws.String("tenant_id = :tid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":tid": &types.AttributeValueMemberS{Value: tenantID},
+		},
+	}
+
+	var items []RecipeItem
+	paginator := dynamodb.NewQueryPaginator(r.DB, queryInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar receitas no DynamoDB: %w", err)
+		}
+		var pageItems []RecipeItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("erro ao deserializar receitas: %w", err)
+		}
+		items = append(items, pageItems...)
+	}
+	return items, nil
+}
+
+func (r *DynamoRecipeRepository) ListRecipes(ctx context.Context, tenantID string) ([]model.Recipe, error) {
+	items, err := r.queryTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].NormalizedName < items[j].NormalizedName })
+
+	recipes := make([]model.Recipe, 0, len(items))
+	for _, item := range items {
+		recipes = append(recipes, item.toRecipe())
+	}
+	return recipes, nil
+}
+
+func (r *DynamoRecipeRepository) UpdateRecipe(ctx context.Context, tenantID, recipeID string, recipe model.Recipe, snapshot model.Food) (*model.Recipe, error) {
+	item := newRecipeItem(tenantID, recipeID, recipe, snapshot)
+	if err := r.putRecipe(ctx, item, "attribute_exists(recipe_id)"); err != nil {
+		return nil, err
+	}
+
+	updated := item.toRecipe()
+	return &updated, nil
+}
+
+func (r *DynamoRecipeRepository) DeleteRecipe(ctx context.Context, tenantID, recipeID string) error {
+	_, err := r.DB.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.TableName),
+		Key:                 recipeKey(tenantID, recipeID),
+		ConditionExpression: aws.String("attribute_exists(recipe_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s", ErrFoodNotFound, recipeID)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao remover receita no DynamoDB: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoRecipeRepository) SearchRecipes(ctx context.Context, tenantID, query string, limit int) ([]model.Food, error) {
+	if normalizeString(query) == "" {
+		return []model.Food{}, nil
+	}
+	items, err := r.queryTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return rankRecipes(query, items, limit), nil
+}
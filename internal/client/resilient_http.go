@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultUpstreamAttempts    = 3
+	defaultUpstreamBaseBackoff = 200 * time.Millisecond
+	defaultUpstreamMaxBackoff  = 2 * time.Second
+
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+var (
+	ErrUpstreamUnavailable = errors.New("fonte externa indisponível")
+	ErrUpstreamRateLimited = errors.New("limite de requisições da fonte externa atingido")
+	ErrCircuitOpen         = errors.New("circuito aberto para a fonte externa")
+	ErrUpstreamBadResponse = errors.New("resposta inválida da fonte externa")
+)
+
+// UpstreamError descreve a falha de uma fonte externa. errors.Is reconhece Kind; limite de
+// requisições e circuito aberto também contam como ErrUpstreamUnavailable, pois em todos
+// esses casos a fonte não pôde responder (diferente de responder sem resultados).
+type UpstreamError struct {
+	Source     string
+	Kind       error
+	StatusCode int
+	// RetryAfter é a espera sugerida antes de nova tentativa, quando conhecida.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Source, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *UpstreamError) Unwrap() []error {
+	errs := []error{e.Kind}
+	if e.Kind == ErrUpstreamRateLimited || e.Kind == ErrCircuitOpen {
+		errs = append(errs, ErrUpstreamUnavailable)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// circuitBreaker abre após threshold chamadas seguidas com falha e rejeita novas chamadas
+// durante o cooldown. Depois disso deixa passar uma chamada de teste: sucesso fecha o
+// circuito, falha o reabre.
+type circuitBreaker struct {
+	source    string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(source string, threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &circuitBreaker{source: source, threshold: threshold, cooldown: cooldown}
+}
+
+// allow informa se a chamada pode seguir e, se não, quanto falta para o circuito testar a fonte.
+func (b *circuitBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return 0, true
+	}
+	if remaining := b.cooldown - time.Since(b.openedAt); remaining > 0 {
+		return remaining, false
+	}
+	if b.probing {
+		return b.cooldown, false
+	}
+	b.probing = true
+	return 0, true
+}
+
+func (b *circuitBreaker) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasProbing := b.probing
+	b.probing = false
+	if ok {
+		if b.failures >= b.threshold {
+			log.Printf("Circuito da fonte %s fechado, fonte voltou a responder", b.source)
+		}
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures == b.threshold || wasProbing {
+		log.Printf("Circuito da fonte %s aberto por %s após %d falhas seguidas", b.source, b.cooldown, b.failures)
+		b.openedAt = time.Now()
+	}
+}
+
+// release libera a chamada de teste sem contar sucesso nem falha (ex: cancelada por quem chamou).
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// resilientHTTPClient faz GETs respeitando o contexto, com limite de taxa do lado do cliente,
+// novas tentativas com backoff exponencial e jitter para 5xx, 429 e falhas de rede, e um
+// circuit breaker por fonte.
+type resilientHTTPClient struct {
+	source      string
+	httpClient  *http.Client
+	userAgent   string
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	breaker     *circuitBreaker
+}
+
+func newResilientHTTPClient(source, userAgent string, timeout time.Duration) *resilientHTTPClient {
+	return &resilientHTTPClient{
+		source:      source,
+		httpClient:  &http.Client{Timeout: timeout},
+		userAgent:   userAgent,
+		maxAttempts: defaultUpstreamAttempts,
+		baseBackoff: defaultUpstreamBaseBackoff,
+		maxBackoff:  defaultUpstreamMaxBackoff,
+		breaker:     newCircuitBreaker(source, DefaultBreakerThreshold, DefaultBreakerCooldown),
+	}
+}
+
+// get devolve a resposta para qualquer status abaixo de 500 exceto 429; quem chama trata 404 e
+// os demais e fecha o corpo. Cancelamento do contexto volta como ctx.Err(), sem contar como
+// falha da fonte.
+func (c *resilientHTTPClient) get(ctx context.Context, limiter *rate.Limiter, url string) (*http.Response, error) {
+	if wait, ok := c.breaker.allow(); !ok {
+		return nil, &UpstreamError{Source: c.source, Kind: ErrCircuitOpen, RetryAfter: wait}
+	}
+
+	var lastErr *UpstreamError
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := c.sleep(ctx, c.backoff(attempt, lastErr.RetryAfter)); err != nil {
+				break
+			}
+		}
+
+		if err := limiter.Wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				c.breaker.release()
+				return nil, ctx.Err()
+			}
+			// A espera pelo limite passaria do prazo de quem chamou.
+			c.breaker.release()
+			return nil, &UpstreamError{Source: c.source, Kind: ErrUpstreamRateLimited, Err: err}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			c.breaker.release()
+			return nil, fmt.Errorf("erro interno ao preparar requisição para %s: %w", c.source, err)
+		}
+		req.Header.Set("User-Agent", c.userAgent)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.breaker.release()
+				return nil, ctx.Err()
+			}
+			log.Printf("Tentativa %d de %d para %s falhou: %v", attempt+1, c.maxAttempts, c.source, err)
+			lastErr = &UpstreamError{Source: c.source, Kind: ErrUpstreamUnavailable, Err: err}
+			continue
+		}
+
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			c.breaker.record(true)
+			return resp, nil
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		log.Printf("Tentativa %d de %d para %s retornou status %d", attempt+1, c.maxAttempts, c.source, resp.StatusCode)
+		lastErr = &UpstreamError{Source: c.source, Kind: ErrUpstreamUnavailable, StatusCode: resp.StatusCode}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			lastErr.Kind = ErrUpstreamRateLimited
+			lastErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+	}
+
+	if ctx.Err() != nil {
+		c.breaker.release()
+		return nil, ctx.Err()
+	}
+	c.breaker.record(false)
+	return nil, lastErr
+}
+
+// backoff devolve a espera antes da tentativa: exponencial com jitter (metade fixa, metade
+// aleatória), ou o Retry-After da fonte quando maior, ambos limitados a maxBackoff.
+func (c *resilientHTTPClient) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	wait := min(c.baseBackoff<<(attempt-1), c.maxBackoff)
+	wait = wait/2 + rand.N(wait/2+1)
+	if retryAfter > wait {
+		wait = min(retryAfter, c.maxBackoff)
+	}
+	return wait
+}
+
+// sleep espera d, desistindo se o contexto terminar antes ou se o prazo não comportar a espera.
+func (c *resilientHTTPClient) sleep(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// newTestResilientClient usa esperas curtas para que os testes não dependam dos valores de produção.
+func newTestResilientClient(threshold int, cooldown time.Duration) *resilientHTTPClient {
+	c := newResilientHTTPClient("Fonte de teste", "teste", time.Second)
+	c.baseBackoff = time.Millisecond
+	c.maxBackoff = 40 * time.Millisecond
+	c.breaker = newCircuitBreaker(c.source, threshold, cooldown)
+	return c
+}
+
+// statusServer responde com statuses[i] na i-ésima requisição e repete o último depois disso.
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(hits.Add(1)) - 1
+		status := statuses[min(i, len(statuses)-1)]
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+var unlimited = rate.NewLimiter(rate.Inf, 1)
+
+func TestResilientGetRetriesTooManyRequestsHonoringRetryAfter(t *testing.T) {
+	c := newTestResilientClient(5, time.Minute)
+	server, hits := statusServer(t, http.Header{"Retry-After": {"1"}}, http.StatusTooManyRequests, http.StatusOK)
+
+	start := time.Now()
+	resp, err := c.get(context.Background(), unlimited, server.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if hits.Load() != 2 {
+		t.Errorf("esperava 2 requisições, houve %d", hits.Load())
+	}
+	// Retry-After de 1 s é limitado a maxBackoff, muito acima do backoff exponencial de 1 ms.
+	if elapsed := time.Since(start); elapsed < c.maxBackoff {
+		t.Errorf("nova tentativa após %s; deveria esperar o Retry-After limitado a %s", elapsed, c.maxBackoff)
+	}
+}
+
+func TestResilientGetReportsRateLimit(t *testing.T) {
+	c := newTestResilientClient(5, time.Minute)
+	server, hits := statusServer(t, http.Header{"Retry-After": {"7"}}, http.StatusTooManyRequests)
+
+	_, err := c.get(context.Background(), unlimited, server.URL)
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) || !errors.Is(err, ErrUpstreamRateLimited) || !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("esperava UpstreamError de limite de requisições, veio %v", err)
+	}
+	if upstream.StatusCode != http.StatusTooManyRequests || upstream.RetryAfter != 7*time.Second {
+		t.Errorf("erro inesperado: %+v", upstream)
+	}
+	if hits.Load() != int32(c.maxAttempts) {
+		t.Errorf("esperava %d tentativas, houve %d", c.maxAttempts, hits.Load())
+	}
+}
+
+func TestResilientGetRetriesServerErrorsUpToLimit(t *testing.T) {
+	cases := []struct {
+		name      string
+		statuses  []int
+		wantHits  int32
+		wantError bool
+	}{
+		{name: "5xx em todas", statuses: []int{http.StatusServiceUnavailable}, wantHits: 3, wantError: true},
+		{name: "recupera na última", statuses: []int{http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK}, wantHits: 3},
+		{name: "404 não é repetido", statuses: []int{http.StatusNotFound}, wantHits: 1},
+		{name: "400 não é repetido", statuses: []int{http.StatusBadRequest}, wantHits: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestResilientClient(5, time.Minute)
+			server, hits := statusServer(t, nil, tc.statuses...)
+
+			resp, err := c.get(context.Background(), unlimited, server.URL)
+			if tc.wantError {
+				var upstream *UpstreamError
+				if !errors.As(err, &upstream) || !errors.Is(err, ErrUpstreamUnavailable) || upstream.StatusCode != tc.statuses[0] {
+					t.Fatalf("esperava UpstreamError com status %d, veio %v", tc.statuses[0], err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				resp.Body.Close()
+			}
+			if hits.Load() != tc.wantHits {
+				t.Errorf("esperava %d requisições, houve %d", tc.wantHits, hits.Load())
+			}
+		})
+	}
+}
+
+func TestResilientGetOpensBreakerAfterThreshold(t *testing.T) {
+	c := newTestResilientClient(2, time.Minute)
+	server, hits := statusServer(t, nil, http.StatusInternalServerError)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.get(context.Background(), unlimited, server.URL); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("chamada %d não deveria encontrar o circuito aberto", i+1)
+		}
+	}
+	before := hits.Load()
+
+	_, err := c.get(context.Background(), unlimited, server.URL)
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) || !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("esperava circuito aberto, veio %v", err)
+	}
+	if upstream.RetryAfter <= 0 || upstream.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter do circuito aberto = %s", upstream.RetryAfter)
+	}
+	if hits.Load() != before {
+		t.Errorf("com o circuito aberto a fonte não deveria ser chamada")
+	}
+}
+
+func TestResilientGetHalfOpenProbe(t *testing.T) {
+	const cooldown = 30 * time.Millisecond
+	c := newTestResilientClient(1, cooldown)
+	server, hits := statusServer(t, nil, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError,
+		http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+
+	// Abre o circuito (3 tentativas).
+	if _, err := c.get(context.Background(), unlimited, server.URL); err == nil {
+		t.Fatalf("primeira chamada deveria falhar")
+	}
+	if _, err := c.get(context.Background(), unlimited, server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("esperava circuito aberto, veio %v", err)
+	}
+
+	// Depois do cooldown, a chamada de teste falha e o circuito reabre na hora.
+	time.Sleep(cooldown)
+	if _, err := c.get(context.Background(), unlimited, server.URL); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("chamada de teste deveria chegar à fonte e falhar, veio %v", err)
+	}
+	if _, err := c.get(context.Background(), unlimited, server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("falha na chamada de teste deveria reabrir o circuito, veio %v", err)
+	}
+
+	// Nova chamada de teste, agora com sucesso: o circuito fecha.
+	time.Sleep(cooldown)
+	resp, err := c.get(context.Background(), unlimited, server.URL)
+	if err != nil {
+		t.Fatalf("chamada de teste deveria ter sucesso: %v", err)
+	}
+	resp.Body.Close()
+	resp, err = c.get(context.Background(), unlimited, server.URL)
+	if err != nil {
+		t.Fatalf("circuito deveria estar fechado: %v", err)
+	}
+	resp.Body.Close()
+	if hits.Load() != 8 {
+		t.Errorf("esperava 8 requisições, houve %d", hits.Load())
+	}
+}
+
+func TestCircuitBreakerAllowsOneProbeAtATime(t *testing.T) {
+	b := newCircuitBreaker("Fonte de teste", 1, time.Millisecond)
+	b.record(false)
+	if _, ok := b.allow(); ok {
+		t.Fatalf("circuito recém-aberto não deveria deixar passar")
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	if _, ok := b.allow(); !ok {
+		t.Fatalf("depois do cooldown deveria deixar passar a chamada de teste")
+	}
+	if _, ok := b.allow(); ok {
+		t.Errorf("só uma chamada de teste por vez")
+	}
+	b.release()
+	if _, ok := b.allow(); !ok {
+		t.Errorf("chamada de teste liberada sem resultado deveria permitir outra")
+	}
+	b.record(true)
+	for i := 0; i < 3; i++ {
+		if _, ok := b.allow(); !ok {
+			t.Errorf("circuito fechado deveria deixar passar")
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	future := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	cases := []struct {
+		value    string
+		min, max time.Duration
+	}{
+		{value: "", min: 0, max: 0},
+		{value: "3", min: 3 * time.Second, max: 3 * time.Second},
+		{value: "0", min: 0, max: 0},
+		{value: "-5", min: 0, max: 0},
+		{value: "logo", min: 0, max: 0},
+		{value: future, min: 85 * time.Second, max: 90 * time.Second},
+		{value: past, min: 0, max: 0},
+	}
+	for _, c := range cases {
+		if got := parseRetryAfter(c.value); got < c.min || got > c.max {
+			t.Errorf("parseRetryAfter(%q) = %s; esperava entre %s e %s", c.value, got, c.min, c.max)
+		}
+	}
+}
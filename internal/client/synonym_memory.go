@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type InMemorySynonymRepository struct {
+	mu      sync.RWMutex
+	entries map[string]SynonymEntry
+}
+
+func NewInMemorySynonymRepository() *InMemorySynonymRepository {
+	return &InMemorySynonymRepository{entries: make(map[string]SynonymEntry)}
+}
+
+func (r *InMemorySynonymRepository) ListSynonyms(ctx context.Context) ([]SynonymEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]SynonymEntry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Term < entries[j].Term })
+	return entries, nil
+}
+
+func (r *InMemorySynonymRepository) PutSynonym(ctx context.Context, entry SynonymEntry) (*SynonymEntry, error) {
+	entry, err := normalizeSynonymEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[entry.Term] = entry
+	return &entry, nil
+}
+
+func (r *InMemorySynonymRepository) DeleteSynonym(ctx context.Context, term string) error {
+	term = normalizeString(term)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[term]; !ok {
+		return fmt.Errorf("%w: %s", ErrSynonymNotFound, term)
+	}
+	delete(r.entries, term)
+	return nil
+}
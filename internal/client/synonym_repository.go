@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	SynonymSourceBuiltin = "builtin"
+	SynonymSourceAdmin   = "admin"
+)
+
+var (
+	ErrInvalidSynonym  = errors.New("sinônimo inválido")
+	ErrSynonymNotFound = errors.New("sinônimo não encontrado")
+)
+
+// SynonymEntry declara que Term e Synonyms nomeiam o mesmo alimento (chave: term). Os termos
+// são guardados já normalizados, sem acentos e em minúsculas.
+type SynonymEntry struct {
+	Term      string   `json:"term" dynamodbav:"term"`
+	Synonyms  []string `json:"synonyms" dynamodbav:"synonyms"`
+	Source    string   `json:"source" dynamodbav:"-"`
+	UpdatedAt string   `json:"updated_at,omitempty" dynamodbav:"updated_at"`
+}
+
+type SynonymRepository interface {
+	ListSynonyms(ctx context.Context) ([]SynonymEntry, error)
+	PutSynonym(ctx context.Context, entry SynonymEntry) (*SynonymEntry, error)
+	DeleteSynonym(ctx context.Context, term string) error
+}
+
+// normalizeSynonymEntry normaliza os termos, remove repetições e o próprio termo da lista
+// de sinônimos e preenche UpdatedAt.
+func normalizeSynonymEntry(entry SynonymEntry) (SynonymEntry, error) {
+	entry.Term = normalizeString(entry.Term)
+	if entry.Term == "" {
+		return entry, fmt.Errorf("%w: term é obrigatório", ErrInvalidSynonym)
+	}
+
+	synonyms := map[string]bool{}
+	for _, s := range entry.Synonyms {
+		normalized := normalizeString(s)
+		if normalized == "" {
+			return entry, fmt.Errorf("%w: sinônimo vazio para '%s'", ErrInvalidSynonym, entry.Term)
+		}
+		if normalized != entry.Term {
+			synonyms[normalized] = true
+		}
+	}
+	if len(synonyms) == 0 {
+		return entry, fmt.Errorf("%w: informe ao menos um sinônimo diferente de '%s'", ErrInvalidSynonym, entry.Term)
+	}
+
+	entry.Synonyms = sortedKeys(synonyms)
+	entry.Source = SynonymSourceAdmin
+	entry.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return entry, nil
+}
+
+type DynamoSynonymRepository struct {
+	DB        *dynamodb.Client
+	TableName string
+}
+
+func NewDynamoSynonymRepository(db *dynamodb.Client, tableName string) *DynamoSynonymRepository {
+	return &DynamoSynonymRepository{DB: db, TableName: tableName}
+}
+
+// ListSynonyms lê a tabela inteira. Ela guarda apenas os sinônimos cadastrados pela
+// curadoria, então é pequena.
+func (r *DynamoSynonymRepository) ListSynonyms(ctx context.Context) ([]SynonymEntry, error) {
+	var entries []SynonymEntry
+	paginator := dynamodb.NewScanPaginator(r.DB, &dynamodb.ScanInput{TableName: aws.String(r.TableName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler sinônimos no DynamoDB: %w", err)
+		}
+		var pageItems []SynonymEntry
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("erro ao fazer unmarshal dos sinônimos: %w", err)
+		}
+		entries = append(entries, pageItems...)
+	}
+	for i := range entries {
+		entries[i].Source = SynonymSourceAdmin
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Term < entries[j].Term })
+	return entries, nil
+}
+
+func (r *DynamoSynonymRepository) PutSynonym(ctx context.Context, entry SynonymEntry) (*SynonymEntry, error) {
+	entry, err := normalizeSynonymEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	av, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao fazer marshal do sinônimo: %w", err)
+	}
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.TableName), Item: av})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar sinônimo no DynamoDB: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *DynamoSynonymRepository) DeleteSynonym(ctx context.Context, term string) error {
+	term = normalizeString(term)
+	_, err := r.DB.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key: map[string]types.AttributeValue{
+			"term": &types.AttributeValueMemberS{Value: term},
+		},
+		ConditionExpression: aws.String("attribute_exists(term)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s", ErrSynonymNotFound, term)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao remover sinônimo no DynamoDB: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultSynonymTTL = time.Minute
+
+// builtinSynonymGroups são nomes regionais do mesmo alimento, já normalizados. O primeiro
+// termo de cada grupo é o usado na TACO. Novos grupos podem ser cadastrados pela curadoria
+// sem deploy, via /api/admin/synonyms.
+var builtinSynonymGroups = [][]string{
+	{"mandioca", "aipim", "macaxeira"},
+	{"abobora", "jerimum"},
+	{"tangerina", "mexerica", "bergamota"},
+	{"mandioquinha", "batata baroa", "batata salsa"},
+	{"charque", "carne seca", "jaba"},
+	{"abacaxi", "ananas"},
+	{"pao frances", "pao de sal", "cacetinho", "pao carioquinha"},
+	{"biscoito", "bolacha"},
+	{"feijao fradinho", "feijao de corda", "feijao macassar"},
+	{"aipo", "salsao"},
+	{"semente de linhaca", "linhaca"},
+}
+
+// SynonymDictionary reúne os sinônimos embutidos e os cadastrados pela curadoria. Mantém os
+// cadastrados em memória e os relê a cada TTL, para que edições feitas em outra instância
+// apareçam aqui.
+type SynonymDictionary struct {
+	repo SynonymRepository
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	synonyms map[string][]string
+	loadedAt time.Time
+}
+
+func NewSynonymDictionary(repo SynonymRepository, ttl time.Duration) *SynonymDictionary {
+	if ttl <= 0 {
+		ttl = DefaultSynonymTTL
+	}
+	return &SynonymDictionary{repo: repo, ttl: ttl}
+}
+
+// Synonyms devolve, para cada termo normalizado, os demais termos do seu grupo. Grupos que
+// compartilham um termo são unidos. Se a leitura falhar, usa a última versão carregada (ou
+// apenas os sinônimos embutidos).
+func (d *SynonymDictionary) Synonyms(ctx context.Context) map[string][]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.synonyms != nil && time.Since(d.loadedAt) < d.ttl {
+		return d.synonyms
+	}
+
+	entries, err := d.repo.ListSynonyms(ctx)
+	if err != nil {
+		log.Printf("Erro ao carregar sinônimos, usando versão anterior: %v", err)
+		if d.synonyms == nil {
+			return buildSynonymMap(builtinSynonymGroups)
+		}
+		return d.synonyms
+	}
+
+	groups := append([][]string(nil), builtinSynonymGroups...)
+	for _, e := range entries {
+		groups = append(groups, append([]string{e.Term}, e.Synonyms...))
+	}
+	d.synonyms = buildSynonymMap(groups)
+	d.loadedAt = time.Now()
+	return d.synonyms
+}
+
+func (d *SynonymDictionary) invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.loadedAt = time.Time{}
+}
+
+// List devolve os grupos embutidos seguidos dos cadastrados pela curadoria.
+func (d *SynonymDictionary) List(ctx context.Context) ([]SynonymEntry, error) {
+	entries, err := d.repo.ListSynonyms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	builtin := make([]SynonymEntry, 0, len(builtinSynonymGroups)+len(entries))
+	for _, group := range builtinSynonymGroups {
+		builtin = append(builtin, SynonymEntry{Term: group[0], Synonyms: group[1:], Source: SynonymSourceBuiltin})
+	}
+	return append(builtin, entries...), nil
+}
+
+func (d *SynonymDictionary) Put(ctx context.Context, entry SynonymEntry) (*SynonymEntry, error) {
+	saved, err := d.repo.PutSynonym(ctx, entry)
+	if err == nil {
+		d.invalidate()
+	}
+	return saved, err
+}
+
+// Delete remove um grupo cadastrado pela curadoria. Os grupos embutidos não podem ser removidos.
+func (d *SynonymDictionary) Delete(ctx context.Context, term string) error {
+	err := d.repo.DeleteSynonym(ctx, term)
+	if err == nil {
+		d.invalidate()
+	}
+	return err
+}
+
+// buildSynonymMap une os grupos que compartilham termos e indexa cada termo pelos demais.
+func buildSynonymMap(groups [][]string) map[string][]string {
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(t string) string {
+		if p, ok := parent[t]; ok && p != t {
+			root := find(p)
+			parent[t] = root
+			return root
+		}
+		parent[t] = t
+		return t
+	}
+
+	for _, group := range groups {
+		for _, term := range group[1:] {
+			parent[find(term)] = find(group[0])
+		}
+	}
+
+	members := map[string][]string{}
+	for term := range parent {
+		root := find(term)
+		members[root] = append(members[root], term)
+	}
+
+	synonyms := make(map[string][]string, len(parent))
+	for term := range parent {
+		group := members[find(term)]
+		others := make([]string, 0, len(group)-1)
+		for _, other := range group {
+			if other != term {
+				others = append(others, other)
+			}
+		}
+		sort.Strings(others)
+		synonyms[term] = others
+	}
+	return synonyms
+}
+
+// queryVariant é a busca com um termo trocado por um sinônimo.
+type queryVariant struct {
+	tokens  []string
+	synonym string
+}
+
+// expandQuery gera uma variante da busca para cada sinônimo de cada termo do dicionário
+// presente nela (como palavras inteiras). A ordem é estável, pois entra na chave do cache.
+func expandQuery(normalizedQuery string, synonyms map[string][]string) []queryVariant {
+	if len(synonyms) == 0 {
+		return nil
+	}
+	queryTokens := strings.Fields(normalizedQuery)
+
+	terms := make([]string, 0, len(synonyms))
+	for term := range synonyms {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	var variants []queryVariant
+	for _, term := range terms {
+		termTokens := strings.Fields(term)
+		at := indexTokens(queryTokens, termTokens)
+		if at < 0 {
+			continue
+		}
+		for _, synonym := range synonyms[term] {
+			tokens := append([]string(nil), queryTokens[:at]...)
+			tokens = append(tokens, strings.Fields(synonym)...)
+			tokens = append(tokens, queryTokens[at+len(termTokens):]...)
+			variants = append(variants, queryVariant{tokens: tokens, synonym: synonym})
+		}
+	}
+	return variants
+}
+
+func indexTokens(tokens, sub []string) int {
+	for i := 0; i+len(sub) <= len(tokens); i++ {
+		match := true
+		for j := range sub {
+			if tokens[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -2,10 +2,10 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"saas-nutri/internal/model"
-	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -18,71 +18,186 @@ type TacoFoodItem struct {
 	DataSource      string  `dynamodbav:"data_source"`
 	NormalizedName  string  `dynamodbav:"normalized_name"`
 	OriginalName    string  `dynamodbav:"original_name"`
+	FoodGroup       string  `dynamodbav:"food_group,omitempty"`
 	EnergyKcal      float64 `dynamodbav:"energy_kcal,omitempty"`
 	ProteinG        float64 `dynamodbav:"protein_g,omitempty"`
 	CarbohydrateG   float64 `dynamodbav:"carbohydrate_g,omitempty"`
 	FatG            float64 `dynamodbav:"fat_g,omitempty"`
 	FiberG          float64 `dynamodbav:"fiber_g,omitempty"`
+	Nutrients       map[string]TacoNutrient `dynamodbav:"nutrients,omitempty"`
+	// MatchedSynonym é preenchido pela busca quando o item foi encontrado por sinônimo.
+	MatchedSynonym  string  `dynamodbav:"-"`
+}
+
+// TacoNutrient guarda um nutriente por 100 g. Value fica ausente quando o status é
+// traço ("Tr") ou não analisado ("NA"), preservando a diferença entre nulo e zero.
+type TacoNutrient struct {
+	Value  *float64 `dynamodbav:"value,omitempty"`
+	Status string   `dynamodbav:"status"`
 }
 
 type TacoRepository struct {
-	DB        *dynamodb.Client
-	TableName string
-	IndexName string
+	DB                *dynamodb.Client
+	TableName         string
+	IndexName         string
+	MeasuresTableName string
+	// View é a cópia em memória da partição TACO usada pela busca e por ListFoods. Pode ser
+	// compartilhada com o NutrientIndex.
+	View *TacoView
 }
 
 type MeasureItem struct {
     FoodID           string  `json:"-" dynamodbav:"food_id"`
     MeasureName      string  `json:"measure_name" dynamodbav:"measure_name"`
     MeasureQuantity  string  `json:"measure_quantity" dynamodbav:"measure_quantity"`
-    DisplayName      string  `json:"display_name"`
+    DisplayName      string  `json:"display_name" dynamodbav:"-"`
     GramEquivalent   float64 `json:"gram_equivalent" dynamodbav:"measure_weight_g"`
 }
 
-func NewTacoRepository(db *dynamodb.Client, tableName, indexName string) *TacoRepository {
-	return &TacoRepository{DB: db, TableName: tableName, IndexName: indexName}
+func MapTacoToFood(tacoItem TacoFoodItem) model.Food {
+	food := model.Food{
+		Id:            tacoItem.FoodID,
+		Name:          tacoItem.OriginalName,
+		Source:        "TACO",
+		FoodGroup:     tacoItem.FoodGroup,
+		EnergyKcal:    tacoItem.EnergyKcal,
+		ProteinG:      tacoItem.ProteinG,
+		CarbohydrateG: tacoItem.CarbohydrateG,
+		FatG:          tacoItem.FatG,
+		FiberG:        tacoItem.FiberG,
+		Nutrients:     mapTacoNutrients(tacoItem.Nutrients),
+		MatchedSynonym: tacoItem.MatchedSynonym,
+	}
+	food.Tags = RuleTags(food)
+	return food
 }
 
-func normalizeString(s string) string {
-	return strings.ToLower(strings.TrimSpace(s))
+func mapTacoNutrients(nutrients map[string]TacoNutrient) map[string]model.NutrientValue {
+	if len(nutrients) == 0 {
+		return nil
+	}
+	mapped := make(map[string]model.NutrientValue, len(nutrients))
+	for key, n := range nutrients {
+		value := model.NutrientValue{Unit: model.NutrientUnit(key), Status: n.Status}
+		if n.Status == model.NutrientStatusMeasured {
+			value.Value = n.Value
+		}
+		mapped[key] = value
+	}
+	return mapped
 }
 
-func (r *TacoRepository) SearchFoodsByNamePrefix(ctx context.Context, namePrefix string) ([]TacoFoodItem, error) {
-	var items []TacoFoodItem
-	normalizedPrefix := normalizeString(namePrefix)
+// foodWithMeasures monta o model.Food de um item TACO com suas medidas caseiras.
+func foodWithMeasures(foodItem TacoFoodItem, measures []MeasureItem) *model.Food {
+	food := MapTacoToFood(foodItem)
+	if foodItem.DataSource != "" {
+		food.Source = foodItem.DataSource
+	}
 
-	if normalizedPrefix == "" {
-		return items, nil
+	var householdMeasures []model.HouseholdMeasure
+	for _, m := range measures {
+		householdMeasures = append(householdMeasures, m.HouseholdMeasure())
 	}
+	food.HouseholdMeasures = householdMeasures
 
-	keyConditionExpression := "data_source = :ds AND begins_with(normalized_name, :prefix)"
-	expressionAttributeValues := map[string]types.AttributeValue{
-		":ds":     &types.AttributeValueMemberS{Value: "TACO"},
-		":prefix": &types.AttributeValueMemberS{Value: normalizedPrefix},
+	return &food
+}
+
+func NewTacoRepository(db *dynamodb.Client, tableName, indexName, measuresTableName string) *TacoRepository {
+	r := &TacoRepository{DB: db, TableName: tableName, IndexName: indexName, MeasuresTableName: measuresTableName}
+	r.View = NewTacoView(r.queryAllTacoFoods)
+	return r
+}
+
+func (r *TacoRepository) SearchFoodsByNamePrefix(ctx context.Context, namePrefix string, filter SearchFilter, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	normalizedQuery := normalizeString(namePrefix)
+
+	if normalizedQuery == "" {
+		return &SearchPage{}, nil
 	}
 
-	projectionExpression := "food_id, data_source, normalized_name, original_name, energy_kcal, protein_g, carbohydrate_g, fat_g, fiber_g"
+	candidates, err := r.View.Items(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	queryInput := &dynamodb.QueryInput{
-		TableName:                 aws.String(r.TableName), 
-		IndexName:                 aws.String(r.IndexName), 
-		KeyConditionExpression:    aws.String(keyConditionExpression),
-		ExpressionAttributeValues: expressionAttributeValues,
-		ProjectionExpression:      aws.String(projectionExpression),
-		Limit:                     aws.Int32(25),
+	page, err := paginateTacoItems(rankTacoItems(normalizedQuery, filter.Synonyms, filter.localizedNames(), filter.filterTacoItems(candidates)), limit, exclusiveStartKey)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Executando Query no DynamoDB GSI '%s' com prefixo: '%s'", r.IndexName, normalizedPrefix)
+	log.Printf("Busca por '%s' retornou %d itens entre %d candidatos (mais páginas: %t)", normalizedQuery, len(page.Items), len(candidates), page.LastEvaluatedKey != nil)
+
+	return page, nil
+}
+
+// ListFoods devolve a partição TACO a partir de View, que a lê uma vez por TTL, e não a cada
+// chamada.
+func (r *TacoRepository) ListFoods(ctx context.Context) ([]TacoFoodItem, error) {
+	return r.View.Items(ctx)
+}
+
+// ListFoodsPage lê uma página da partição TACO do GSI. Ao contrário de ListFoods, não
+// materializa a base inteira, então serve para percorrer a tabela sob demanda.
+func (r *TacoRepository) ListFoodsPage(ctx context.Context, limit int, exclusiveStartKey map[string]types.AttributeValue) (*SearchPage, error) {
+	queryInput := r.tacoPartitionQuery()
+	queryInput.Limit = aws.Int32(int32(limit))
+	if len(exclusiveStartKey) > 0 {
+		queryInput.ExclusiveStartKey = exclusiveStartKey
+	}
 
 	result, err := r.DB.Query(ctx, queryInput)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao executar query no DynamoDB: %w", err)
 	}
 
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &items)
-	if err != nil {
+	var items []TacoFoodItem
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
 		return nil, fmt.Errorf("erro ao fazer unmarshal dos resultados do DynamoDB: %w", err)
 	}
+	return &SearchPage{Items: items, LastEvaluatedKey: result.LastEvaluatedKey}, nil
+}
+
+// tacoPartitionQuery monta a query de todos os alimentos da partição TACO no GSI.
+func (r *TacoRepository) tacoPartitionQuery() *dynamodb.QueryInput {
+	keyConditionExpression := "data_source = :ds"
+	expressionAttributeValues := map[string]types.AttributeValue{
+		":ds": &types.AttributeValueMemberS{Value: "TACO"},
+	}
+
+	projectionExpression := "food_id, data_source, normalized_name, original_name, food_group, energy_kcal, protein_g, carbohydrate_g, fat_g, fiber_g, nutrients"
+
+	return &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String(r.IndexName),
+		KeyConditionExpression:    aws.String(keyConditionExpression),
+		ExpressionAttributeValues: expressionAttributeValues,
+		ProjectionExpression:      aws.String(projectionExpression),
+	}
+}
+
+// queryAllTacoFoods lê toda a partição TACO do GSI, seguindo LastEvaluatedKey.
+// A base TACO é pequena e fixa, então o ranking por similaridade é feito em memória, sobre a
+// cópia mantida por View.
+func (r *TacoRepository) queryAllTacoFoods(ctx context.Context) ([]TacoFoodItem, error) {
+	var items []TacoFoodItem
+
+	log.Printf("Executando Query no DynamoDB GSI '%s' para a partição TACO", r.IndexName)
+
+	paginator := dynamodb.NewQueryPaginator(r.DB, r.tacoPartitionQuery())
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao executar query no DynamoDB: %w", err)
+		}
+
+		var pageItems []TacoFoodItem
+		err = attributevalue.UnmarshalListOfMaps(page.Items, &pageItems)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer unmarshal dos resultados do DynamoDB: %w", err)
+		}
+		items = append(items, pageItems...)
+	}
 
 	log.Printf("DynamoDB Query retornou %d itens", len(items))
 
@@ -108,7 +223,7 @@ func (r *TacoRepository) GetMeasuresForFood(ctx context.Context, foodID string)
     log.Printf("Buscando medidas para food_id: %s", foodID)
 
     queryInput := &dynamodb.QueryInput{
-        TableName:                 aws.String("HouseholdMeasures"),
+        TableName:                 aws.String(r.MeasuresTableName),
         KeyConditionExpression:    aws.String(keyConditionExpression),
         ExpressionAttributeValues: expressionAttributeValues,
         ProjectionExpression:      aws.String(projectionExpression),
@@ -135,25 +250,12 @@ func (r *TacoRepository) GetMeasuresForFood(ctx context.Context, foodID string)
     log.Printf("Após unmarshal: %+v", dbMeasures)
 
     for i := range dbMeasures {
-        if dbMeasures[i].MeasureQuantity != "" {
-            dbMeasures[i].DisplayName = dbMeasures[i].MeasureQuantity + " " + dbMeasures[i].MeasureName
-        } else {
-            dbMeasures[i].DisplayName = dbMeasures[i].MeasureName
-        }
+        dbMeasures[i].DisplayName = measureDisplayName(dbMeasures[i])
         log.Printf("Medida %d: name=%s, quantity=%s, weight=%f, display=%s", 
             i, dbMeasures[i].MeasureName, dbMeasures[i].MeasureQuantity, 
             dbMeasures[i].GramEquivalent, dbMeasures[i].DisplayName)
     }
 
-    testItem := MeasureItem{
-        FoodID:          foodID,
-        MeasureName:     "colher de sopa",
-        MeasureQuantity: "1",
-        DisplayName:     "1 colher de sopa",
-        GramEquivalent:  15.0,
-    }
-    items = append(items, testItem)
-    
     items = append(items, dbMeasures...)
     return items, nil
 }
@@ -174,7 +276,7 @@ func (r *TacoRepository) GetFoodWithMeasures(ctx context.Context, foodID string)
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("alimento não encontrado: %s", foodID)
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
 	}
 
 	var foodItem TacoFoodItem
@@ -183,31 +285,148 @@ func (r *TacoRepository) GetFoodWithMeasures(ctx context.Context, foodID string)
 		return nil, fmt.Errorf("erro ao deserializar alimento: %w", err)
 	}
 
-	food := &model.Food{
-		Id:            foodItem.FoodID,
-		Name:          foodItem.NormalizedName, 
-		Source:        foodItem.DataSource,
-		EnergyKcal:    foodItem.EnergyKcal,
-		ProteinG:      foodItem.ProteinG,
-		CarbohydrateG: foodItem.CarbohydrateG,
-		FatG:          foodItem.FatG,
-		FiberG:        foodItem.FiberG,
-	}
-
 	measures, err := r.GetMeasuresForFood(ctx, foodID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar medidas caseiras: %w", err)
 	}
 
-	var householdMeasures []model.HouseholdMeasure
-	for _, m := range measures {
-		householdMeasures = append(householdMeasures, model.HouseholdMeasure{
-			Name:  m.DisplayName,
-			Grams: m.GramEquivalent,
+	return foodWithMeasures(foodItem, measures), nil
+}
+
+func (r *TacoRepository) CreateMeasure(ctx context.Context, measure MeasureItem) (*MeasureItem, error) {
+	if err := r.checkMeasureWrite(ctx, measure, ""); err != nil {
+		return nil, err
+	}
+
+	av, err := attributevalue.MarshalMap(measure)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar medida: %w", err)
+	}
+
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.MeasuresTableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(food_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil, fmt.Errorf("%w: %s", ErrMeasureExists, measure.MeasureName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar medida no DB para food_id %s: %w", measure.FoodID, err)
+	}
+
+	log.Printf("Medida '%s' criada para food_id: %s", measure.MeasureName, measure.FoodID)
+	measure.DisplayName = measureDisplayName(measure)
+	return &measure, nil
+}
+
+// UpdateMeasure altera a medida measureName. Se measure.MeasureName for diferente, a medida é
+// renomeada em uma transação (remove a antiga e grava a nova), já que o nome faz parte da chave.
+func (r *TacoRepository) UpdateMeasure(ctx context.Context, foodID, measureName string, measure MeasureItem) (*MeasureItem, error) {
+	measure.FoodID = foodID
+	if measure.MeasureName == "" {
+		measure.MeasureName = measureName
+	}
+	if err := r.checkMeasureWrite(ctx, measure, measureName); err != nil {
+		return nil, err
+	}
+
+	av, err := attributevalue.MarshalMap(measure)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar medida: %w", err)
+	}
+
+	if measure.MeasureName == measureName {
+		_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(r.MeasuresTableName),
+			Item:                av,
+			ConditionExpression: aws.String("attribute_exists(food_id)"),
 		})
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return nil, fmt.Errorf("%w: %s", ErrMeasureNotFound, measureName)
+		}
+	} else {
+		_, err = r.DB.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{
+				{Delete: &types.Delete{
+					TableName:           aws.String(r.MeasuresTableName),
+					Key:                 measureKey(foodID, measureName),
+					ConditionExpression: aws.String("attribute_exists(food_id)"),
+				}},
+				{Put: &types.Put{
+					TableName:           aws.String(r.MeasuresTableName),
+					Item:                av,
+					ConditionExpression: aws.String("attribute_not_exists(food_id)"),
+				}},
+			},
+		})
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			reasons := canceled.CancellationReasons
+			if len(reasons) > 0 && aws.ToString(reasons[0].Code) == "ConditionalCheckFailed" {
+				return nil, fmt.Errorf("%w: %s", ErrMeasureNotFound, measureName)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrMeasureExists, measure.MeasureName)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao atualizar medida no DB para food_id %s: %w", foodID, err)
 	}
 
-	food.HouseholdMeasures = householdMeasures
+	log.Printf("Medida '%s' atualizada para food_id: %s", measureName, foodID)
+	measure.DisplayName = measureDisplayName(measure)
+	return &measure, nil
+}
+
+func (r *TacoRepository) DeleteMeasure(ctx context.Context, foodID, measureName string) error {
+	_, err := r.DB.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.MeasuresTableName),
+		Key:                 measureKey(foodID, measureName),
+		ConditionExpression: aws.String("attribute_exists(food_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s", ErrMeasureNotFound, measureName)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao remover medida no DB para food_id %s: %w", foodID, err)
+	}
 
-	return food, nil
-}
\ No newline at end of file
+	log.Printf("Medida '%s' removida de food_id: %s", measureName, foodID)
+	return nil
+}
+
+// checkMeasureWrite valida a medida, confirma que o alimento existe e rejeita nomes que,
+// normalizados, coincidam com outra medida do alimento (exceto a própria, em edições).
+func (r *TacoRepository) checkMeasureWrite(ctx context.Context, measure MeasureItem, currentName string) error {
+	if err := ValidateMeasure(measure); err != nil {
+		return err
+	}
+
+	food, err := r.DB.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:            aws.String(r.TableName),
+		Key:                  map[string]types.AttributeValue{"food_id": &types.AttributeValueMemberS{Value: measure.FoodID}},
+		ProjectionExpression: aws.String("food_id"),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao buscar alimento no DynamoDB: %w", err)
+	}
+	if food.Item == nil {
+		return fmt.Errorf("%w: %s", ErrFoodNotFound, measure.FoodID)
+	}
+
+	existing, err := r.GetMeasuresForFood(ctx, measure.FoodID)
+	if err != nil {
+		return err
+	}
+	return checkDuplicateMeasure(existing, measure.MeasureName, currentName)
+}
+
+func measureKey(foodID, measureName string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"food_id":      &types.AttributeValueMemberS{Value: foodID},
+		"measure_name": &types.AttributeValueMemberS{Value: measureName},
+	}
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// embeddedTacoSnapshot é o snapshot da TACO compilado no binário. O arquivo versionado traz
+// só uma amostra, marcada com "sample": true, porque a planilha oficial não acompanha o
+// repositório; para embutir a tabela completa, gere-o a partir dela com
+// "go run ./cmd/taco-import --taco taco.csv --measures medidas.csv --snapshot internal/client/taco_snapshot.json"
+// e recompile.
+//
+//go:embed taco_snapshot.json
+var embeddedTacoSnapshot []byte
+
+// TacoSnapshot é o formato JSON do snapshot: os mesmos dados das tabelas TacoFoods e
+// HouseholdMeasures.
+type TacoSnapshot struct {
+	GeneratedAt string            `json:"generated_at"`
+	Sample      bool              `json:"sample,omitempty"`
+	Foods       []snapshotFood    `json:"foods"`
+	Measures    []snapshotMeasure `json:"measures"`
+}
+
+type snapshotNutrient struct {
+	Value  *float64 `json:"value,omitempty"`
+	Status string   `json:"status"`
+}
+
+type snapshotFood struct {
+	FoodID        string                      `json:"food_id"`
+	OriginalName  string                      `json:"original_name"`
+	FoodGroup     string                      `json:"food_group,omitempty"`
+	EnergyKcal    float64                     `json:"energy_kcal"`
+	ProteinG      float64                     `json:"protein_g"`
+	CarbohydrateG float64                     `json:"carbohydrate_g"`
+	FatG          float64                     `json:"fat_g"`
+	FiberG        float64                     `json:"fiber_g"`
+	Nutrients     map[string]snapshotNutrient `json:"nutrients,omitempty"`
+}
+
+type snapshotMeasure struct {
+	FoodID          string  `json:"food_id"`
+	MeasureName     string  `json:"measure_name"`
+	MeasureQuantity string  `json:"measure_quantity"`
+	GramEquivalent  float64 `json:"measure_weight_g"`
+}
+
+func NewTacoSnapshot(foods []TacoFoodItem, measures []MeasureItem) TacoSnapshot {
+	snapshot := TacoSnapshot{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Foods:       make([]snapshotFood, 0, len(foods)),
+		Measures:    make([]snapshotMeasure, 0, len(measures)),
+	}
+	for _, f := range foods {
+		var nutrients map[string]snapshotNutrient
+		if len(f.Nutrients) > 0 {
+			nutrients = make(map[string]snapshotNutrient, len(f.Nutrients))
+			for key, n := range f.Nutrients {
+				nutrients[key] = snapshotNutrient{Value: n.Value, Status: n.Status}
+			}
+		}
+		snapshot.Foods = append(snapshot.Foods, snapshotFood{
+			FoodID:        f.FoodID,
+			OriginalName:  f.OriginalName,
+			FoodGroup:     f.FoodGroup,
+			EnergyKcal:    f.EnergyKcal,
+			ProteinG:      f.ProteinG,
+			CarbohydrateG: f.CarbohydrateG,
+			FatG:          f.FatG,
+			FiberG:        f.FiberG,
+			Nutrients:     nutrients,
+		})
+	}
+	for _, m := range measures {
+		snapshot.Measures = append(snapshot.Measures, snapshotMeasure{
+			FoodID:          m.FoodID,
+			MeasureName:     m.MeasureName,
+			MeasureQuantity: m.MeasureQuantity,
+			GramEquivalent:  m.GramEquivalent,
+		})
+	}
+	return snapshot
+}
+
+func (s TacoSnapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(s)
+}
+
+// ReadTacoSnapshot lê um snapshot e devolve os itens no formato das tabelas, com
+// normalized_name recalculado.
+func ReadTacoSnapshot(r io.Reader) ([]TacoFoodItem, []MeasureItem, error) {
+	var snapshot TacoSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler snapshot TACO: %w", err)
+	}
+
+	foods := make([]TacoFoodItem, 0, len(snapshot.Foods))
+	for _, f := range snapshot.Foods {
+		var nutrients map[string]TacoNutrient
+		if len(f.Nutrients) > 0 {
+			nutrients = make(map[string]TacoNutrient, len(f.Nutrients))
+			for key, n := range f.Nutrients {
+				nutrients[key] = TacoNutrient{Value: n.Value, Status: n.Status}
+			}
+		}
+		foods = append(foods, TacoFoodItem{
+			FoodID:         f.FoodID,
+			DataSource:     "TACO",
+			NormalizedName: normalizeString(f.OriginalName),
+			OriginalName:   f.OriginalName,
+			FoodGroup:      f.FoodGroup,
+			EnergyKcal:     f.EnergyKcal,
+			ProteinG:       f.ProteinG,
+			CarbohydrateG:  f.CarbohydrateG,
+			FatG:           f.FatG,
+			FiberG:         f.FiberG,
+			Nutrients:      nutrients,
+		})
+	}
+
+	measures := make([]MeasureItem, 0, len(snapshot.Measures))
+	for _, m := range snapshot.Measures {
+		measures = append(measures, MeasureItem{
+			FoodID:          m.FoodID,
+			MeasureName:     m.MeasureName,
+			MeasureQuantity: m.MeasureQuantity,
+			GramEquivalent:  m.GramEquivalent,
+		})
+	}
+	return foods, measures, nil
+}
+
+// EmbeddedTacoSnapshotIsSample informa se o binário foi compilado com a amostra versionada em
+// vez da tabela completa gerada pelo taco-import.
+func EmbeddedTacoSnapshotIsSample() bool {
+	var snapshot struct {
+		Sample bool `json:"sample"`
+	}
+	return json.Unmarshal(embeddedTacoSnapshot, &snapshot) == nil && snapshot.Sample
+}
+
+// NewEmbeddedFoodRepository carrega o snapshot embutido num repositório em memória. Edições
+// de medidas funcionam, mas se perdem ao reiniciar.
+func NewEmbeddedFoodRepository() (*InMemoryFoodRepository, error) {
+	foods, measures, err := ReadTacoSnapshot(bytes.NewReader(embeddedTacoSnapshot))
+	if err != nil {
+		return nil, err
+	}
+	return NewInMemoryFoodRepository(foods, measures), nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"saas-nutri/internal/model"
+	"strconv"
+	"strings"
+)
+
+// ambiguousThousands casa números como "1.234" e "12.345", em que o ponto pode ser decimal ou
+// de milhar. "0.123" e "1234.5" não são ambíguos.
+var ambiguousThousands = regexp.MustCompile(`^[1-9][0-9]{0,2}\.[0-9]{3}$`)
+
+// ParseTacoValue interpreta uma célula numérica da planilha TACO: decimal com vírgula,
+// "Tr" para traço e "NA", "*" ou vazio para não analisado.
+func ParseTacoValue(raw string) (TacoNutrient, error) {
+	cleaned := strings.TrimSpace(raw)
+
+	switch strings.ToLower(cleaned) {
+	case "tr", "tr.":
+		return TacoNutrient{Status: model.NutrientStatusTrace}, nil
+	case "na", "n/a", "*", "-", "":
+		return TacoNutrient{Status: model.NutrientStatusNotAnalyzed}, nil
+	}
+
+	normalized := strings.ReplaceAll(cleaned, ".", "")
+	normalized = strings.Replace(normalized, ",", ".", 1)
+	if !strings.Contains(cleaned, ",") {
+		// Sem vírgula o ponto, se houver, é separador decimal (planilhas reexportadas). Com
+		// exatamente três dígitos depois dele ("1.234"), pode ser também separador de milhar:
+		// em vez de adivinhar, o valor é recusado.
+		if ambiguousThousands.MatchString(cleaned) {
+			return TacoNutrient{}, fmt.Errorf("valor ambíguo: '%s' pode ser %s ou %s; use vírgula como separador decimal", raw, strings.Replace(cleaned, ".", ",", 1), strings.ReplaceAll(cleaned, ".", ""))
+		}
+		normalized = cleaned
+	}
+
+	value, err := strconv.ParseFloat(normalized, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return TacoNutrient{}, fmt.Errorf("valor numérico inválido: '%s'", raw)
+	}
+	if value < 0 {
+		return TacoNutrient{}, fmt.Errorf("valor negativo: '%s'", raw)
+	}
+	return TacoNutrient{Value: &value, Status: model.NutrientStatusMeasured}, nil
+}
+
+// Float devolve o valor medido, ou zero para traço e não analisado.
+func (n TacoNutrient) Float() float64 {
+	if n.Value == nil {
+		return 0
+	}
+	return *n.Value
+}
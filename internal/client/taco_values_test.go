@@ -0,0 +1,52 @@
+package client
+
+import (
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+func TestParseTacoValue(t *testing.T) {
+	tests := []struct {
+		raw    string
+		value  float64
+		status string
+	}{
+		{"1,5", 1.5, model.NutrientStatusMeasured},
+		{"1.234,5", 1234.5, model.NutrientStatusMeasured},
+		{"2.5", 2.5, model.NutrientStatusMeasured},
+		{"0.123", 0.123, model.NutrientStatusMeasured},
+		{"1234.567", 1234.567, model.NutrientStatusMeasured},
+		{"12", 12, model.NutrientStatusMeasured},
+		{" 0,8 ", 0.8, model.NutrientStatusMeasured},
+		{"0", 0, model.NutrientStatusMeasured},
+		{"12.345,67", 12345.67, model.NutrientStatusMeasured},
+		{"1.234.567,8", 1234567.8, model.NutrientStatusMeasured},
+		{"Tr", 0, model.NutrientStatusTrace},
+		{"tr.", 0, model.NutrientStatusTrace},
+		{"NA", 0, model.NutrientStatusNotAnalyzed},
+		{"n/a", 0, model.NutrientStatusNotAnalyzed},
+		{"*", 0, model.NutrientStatusNotAnalyzed},
+		{"-", 0, model.NutrientStatusNotAnalyzed},
+		{"", 0, model.NutrientStatusNotAnalyzed},
+	}
+	for _, tt := range tests {
+		got, err := ParseTacoValue(tt.raw)
+		if err != nil {
+			t.Errorf("ParseTacoValue(%q): %v", tt.raw, err)
+			continue
+		}
+		if got.Status != tt.status || got.Float() != tt.value || (got.Value == nil) != (tt.status != model.NutrientStatusMeasured) {
+			t.Errorf("ParseTacoValue(%q) = %v (%s), esperava %v (%s)", tt.raw, got.Float(), got.Status, tt.value, tt.status)
+		}
+	}
+}
+
+func TestParseTacoValueRejects(t *testing.T) {
+	// "1.234" e "12.345" podem ter o ponto decimal ou de milhar.
+	for _, raw := range []string{"1.234", "12.345", "999.000", "abc", "-1,5", "1,2,3", "NaN", "Inf", "-Inf", "infinity"} {
+		if got, err := ParseTacoValue(raw); err == nil {
+			t.Errorf("ParseTacoValue(%q) = %v, esperava erro", raw, got.Float())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultTacoViewTTL é quanto tempo a cópia em memória da partição TACO vale antes de ser
+// relida. A TACO só muda por importação, então uma importação aparece na busca e nas
+// consultas por nutrientes em até esse tempo.
+const DefaultTacoViewTTL = 10 * time.Minute
+
+// TacoView é a visão materializada da partição TACO. Uma única instância atende a busca do
+// TacoRepository e o NutrientIndex, para que a base fique uma vez só em memória e as duas
+// vejam a mesma versão. Se a releitura falhar, continua servindo a última cópia boa.
+type TacoView struct {
+	load func(ctx context.Context) ([]TacoFoodItem, error)
+	// TTL substitui DefaultTacoViewTTL quando positivo.
+	TTL time.Duration
+
+	mu       sync.Mutex
+	items    []TacoFoodItem
+	loadedAt time.Time
+}
+
+// NewTacoView cria a visão sobre load, que deve ler a partição inteira da fonte.
+func NewTacoView(load func(ctx context.Context) ([]TacoFoodItem, error)) *TacoView {
+	return &TacoView{load: load}
+}
+
+// Invalidate força a releitura no próximo acesso. A última cópia continua valendo se a
+// releitura falhar.
+func (v *TacoView) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.loadedAt = time.Time{}
+}
+
+// Items devolve a partição TACO, relendo-a se passou do TTL. O slice é compartilhado e não
+// deve ser alterado.
+func (v *TacoView) Items(ctx context.Context) ([]TacoFoodItem, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ttl := v.TTL
+	if ttl <= 0 {
+		ttl = DefaultTacoViewTTL
+	}
+	if v.items != nil && time.Since(v.loadedAt) < ttl {
+		return v.items, nil
+	}
+
+	items, err := v.load(ctx)
+	if err != nil {
+		if v.items != nil {
+			log.Printf("Erro ao recarregar a partição TACO, usando versão de %s: %v", v.loadedAt.Format(time.RFC3339), err)
+			return v.items, nil
+		}
+		return nil, err
+	}
+	if items == nil {
+		items = []TacoFoodItem{}
+	}
+	v.items = items
+	v.loadedAt = time.Now()
+	log.Printf("Partição TACO carregada em memória com %d alimentos", len(items))
+	return items, nil
+}
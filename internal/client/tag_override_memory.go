@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type InMemoryTagOverrideRepository struct {
+	mu        sync.RWMutex
+	overrides map[string]TagOverride
+}
+
+func NewInMemoryTagOverrideRepository() *InMemoryTagOverrideRepository {
+	return &InMemoryTagOverrideRepository{overrides: make(map[string]TagOverride)}
+}
+
+func (r *InMemoryTagOverrideRepository) ListTagOverrides(ctx context.Context) ([]TagOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overrides := make([]TagOverride, 0, len(r.overrides))
+	for _, o := range r.overrides {
+		overrides = append(overrides, o)
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].FoodID < overrides[j].FoodID })
+	return overrides, nil
+}
+
+func (r *InMemoryTagOverrideRepository) PutTagOverride(ctx context.Context, override TagOverride) (*TagOverride, error) {
+	override, err := normalizeTagOverride(override)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[override.FoodID] = override
+	return &override, nil
+}
+
+func (r *InMemoryTagOverrideRepository) DeleteTagOverride(ctx context.Context, foodID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.overrides[foodID]; !ok {
+		return fmt.Errorf("%w: %s", ErrTagOverrideNotFound, foodID)
+	}
+	delete(r.overrides, foodID)
+	return nil
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"saas-nutri/internal/model"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var (
+	ErrInvalidTags         = errors.New("tags inválidas")
+	ErrTagOverrideNotFound = errors.New("correção de tags não encontrada")
+)
+
+// TagOverride é a correção manual das tags de um alimento, feita por curadoria. Substitui
+// por completo as tags calculadas (chave: food_id).
+type TagOverride struct {
+	FoodID    string   `json:"food_id" dynamodbav:"food_id"`
+	Allergens []string `json:"allergens" dynamodbav:"allergens"`
+	Diets     []string `json:"diets" dynamodbav:"diets"`
+	Note      string   `json:"note,omitempty" dynamodbav:"note,omitempty"`
+	UpdatedAt string   `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+type TagOverrideRepository interface {
+	ListTagOverrides(ctx context.Context) ([]TagOverride, error)
+	PutTagOverride(ctx context.Context, override TagOverride) (*TagOverride, error)
+	DeleteTagOverride(ctx context.Context, foodID string) error
+}
+
+func (o TagOverride) tags() model.FoodTags {
+	return model.FoodTags{Allergens: o.Allergens, Diets: o.Diets, Source: model.TagSourceOverride}
+}
+
+// normalizeTagOverride valida alérgenos e dietas, remove repetições e preenche UpdatedAt.
+func normalizeTagOverride(override TagOverride) (TagOverride, error) {
+	if override.FoodID == "" {
+		return override, fmt.Errorf("%w: food_id é obrigatório", ErrInvalidTags)
+	}
+
+	allergens := map[string]bool{}
+	for _, a := range override.Allergens {
+		if !model.IsAllergen(a) {
+			return override, fmt.Errorf("%w: alérgeno '%s' desconhecido", ErrInvalidTags, a)
+		}
+		allergens[a] = true
+	}
+	diets := map[string]bool{}
+	for _, d := range override.Diets {
+		if !model.IsDiet(d) {
+			return override, fmt.Errorf("%w: dieta '%s' desconhecida", ErrInvalidTags, d)
+		}
+		diets[d] = true
+	}
+	if diets[model.DietVegan] && !diets[model.DietVegetarian] {
+		diets[model.DietVegetarian] = true
+	}
+
+	override.Allergens = sortedKeys(allergens)
+	override.Diets = sortedKeys(diets)
+	override.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return override, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type DynamoTagOverrideRepository struct {
+	DB        *dynamodb.Client
+	TableName string
+}
+
+func NewDynamoTagOverrideRepository(db *dynamodb.Client, tableName string) *DynamoTagOverrideRepository {
+	return &DynamoTagOverrideRepository{DB: db, TableName: tableName}
+}
+
+// ListTagOverrides lê a tabela inteira. Ela guarda apenas as correções manuais, então é pequena.
+func (r *DynamoTagOverrideRepository) ListTagOverrides(ctx context.Context) ([]TagOverride, error) {
+	var overrides []TagOverride
+	paginator := dynamodb.NewScanPaginator(r.DB, &dynamodb.ScanInput{TableName: aws.String(r.TableName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler correções de tags no DynamoDB: %w", err)
+		}
+		var pageItems []TagOverride
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("erro ao fazer unmarshal das correções de tags: %w", err)
+		}
+		overrides = append(overrides, pageItems...)
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].FoodID < overrides[j].FoodID })
+	return overrides, nil
+}
+
+func (r *DynamoTagOverrideRepository) PutTagOverride(ctx context.Context, override TagOverride) (*TagOverride, error) {
+	override, err := normalizeTagOverride(override)
+	if err != nil {
+		return nil, err
+	}
+	av, err := attributevalue.MarshalMap(override)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao fazer marshal da correção de tags: %w", err)
+	}
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.TableName), Item: av})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar correção de tags no DynamoDB: %w", err)
+	}
+	return &override, nil
+}
+
+func (r *DynamoTagOverrideRepository) DeleteTagOverride(ctx context.Context, foodID string) error {
+	_, err := r.DB.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key: map[string]types.AttributeValue{
+			"food_id": &types.AttributeValueMemberS{Value: foodID},
+		},
+		ConditionExpression: aws.String("attribute_exists(food_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s", ErrTagOverrideNotFound, foodID)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao remover correção de tags no DynamoDB: %w", err)
+	}
+	return nil
+}
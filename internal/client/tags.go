@@ -0,0 +1,191 @@
+package client
+
+import (
+	"saas-nutri/internal/model"
+	"sort"
+	"strings"
+)
+
+// allergenKeywords relaciona termos (já normalizados) do nome do alimento a alérgenos.
+// A TACO não declara alérgenos, então a detecção é feita pelo nome.
+var allergenKeywords = map[string][]string{
+	model.AllergenGluten: {
+		"trigo", "pao", "paes", "macarrao", "biscoito", "bolacha", "bolo", "torrada", "cevada",
+		"centeio", "aveia", "malte", "pizza", "lasanha", "nhoque", "pastel", "coxinha", "quibe",
+		"esfiha", "empada", "croissant", "panetone", "cerveja", "farinha lactea", "cuscuz marroquino",
+	},
+	model.AllergenLactose: {
+		"leite", "queijo", "iogurte", "requeijao", "manteiga", "creme de leite", "nata", "ricota",
+		"coalhada", "sorvete", "chantilly", "bebida lactea", "pudim", "doce de leite", "lactea",
+	},
+	model.AllergenNuts: {
+		"castanha", "castanhas", "noz", "nozes", "amendoa", "amendoas", "avela", "pistache",
+		"macadamia", "peca", "amendoim", "pacoca", "pe de moleque",
+	},
+	model.AllergenShellfish: {
+		"camarao", "camaroes", "lagosta", "caranguejo", "siri", "lula", "polvo", "marisco",
+		"mexilhao", "ostra", "sururu", "vieira", "sarnambi",
+	},
+	model.AllergenEgg: {
+		"ovo", "ovos", "gema", "maionese", "omelete", "suspiro", "quindim", "gemada", "pudim",
+	},
+	model.AllergenSoy: {
+		"soja", "tofu", "shoyu", "miso", "edamame", "tempeh",
+	},
+}
+
+// allergenExceptions anula a detecção quando o termo aparece num nome que não contém o alérgeno.
+var allergenExceptions = map[string][]string{
+	model.AllergenLactose: {"leite de coco", "leite de soja", "leite de amendoa", "leite de castanha", "sem lactose", "manteiga de cacau"},
+	model.AllergenGluten:  {"sem gluten", "pao de queijo"},
+}
+
+// groupAllergens marca alérgenos implícitos no grupo do alimento.
+var groupAllergens = map[string]string{
+	"leites": model.AllergenLactose,
+	"ovos":   model.AllergenEgg,
+}
+
+func containsTerm(padded, term string) bool {
+	return strings.Contains(padded, " "+term+" ")
+}
+
+// DetectAllergens devolve os alérgenos prováveis do alimento a partir do nome e do grupo,
+// em ordem alfabética.
+func DetectAllergens(food model.Food) []string {
+	padded := " " + normalizeString(food.Name) + " "
+
+	found := map[string]bool{}
+	if allergen, ok := groupAllergens[food.FoodGroup]; ok {
+		found[allergen] = true
+	}
+	for allergen, keywords := range allergenKeywords {
+		excepted := false
+		for _, exception := range allergenExceptions[allergen] {
+			if containsTerm(padded, exception) {
+				excepted = true
+				break
+			}
+		}
+		if excepted {
+			continue
+		}
+		for _, keyword := range keywords {
+			if containsTerm(padded, keyword) {
+				found[allergen] = true
+				break
+			}
+		}
+	}
+
+	allergens := make([]string, 0, len(found))
+	for allergen := range found {
+		allergens = append(allergens, allergen)
+	}
+	sort.Strings(allergens)
+	return allergens
+}
+
+// animalKeywords marcam alimentos de origem animal (não vegetarianos).
+var animalKeywords = []string{
+	"carne", "carnes", "frango", "galinha", "peru", "pato", "boi", "bovina", "bovino", "porco",
+	"suina", "suino", "lombo", "bacon", "toucinho", "presunto", "salame", "salsicha", "linguica",
+	"mortadela", "charque", "figado", "coracao", "moela", "mocoto", "bucho", "lingua", "peixe",
+	"atum", "sardinha", "bacalhau", "salmao", "merluza", "pescada", "tilapia", "corvina",
+	"gelatina", "banha", "feijoada", "hamburguer", "almondega", "quibe", "coxinha", "kibe",
+}
+
+// animalExceptions anula animalKeywords para versões vegetais.
+var animalExceptions = []string{"de soja", "vegetal", "vegano", "vegana", "sem carne"}
+
+// nonVeganKeywords marcam derivados animais aceitos na dieta vegetariana.
+var nonVeganKeywords = []string{"mel", "manteiga"}
+
+// animalGroups são grupos sem alimentos vegetarianos; derivedGroups não têm alimentos veganos.
+var (
+	animalGroups  = map[string]bool{"carnes": true, "pescados": true}
+	derivedGroups = map[string]bool{"leites": true, "ovos": true}
+)
+
+func containsAnyTerm(padded string, terms []string) bool {
+	for _, term := range terms {
+		if containsTerm(padded, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectDiets devolve as dietas compatíveis a partir do nome, do grupo e dos alérgenos.
+func detectDiets(food model.Food, allergens []string) []string {
+	padded := " " + normalizeString(food.Name) + " "
+	tags := model.FoodTags{Allergens: allergens}
+
+	animal := animalGroups[food.FoodGroup] || tags.HasAllergen(model.AllergenShellfish) ||
+		(containsAnyTerm(padded, animalKeywords) && !containsAnyTerm(padded, animalExceptions))
+	if animal {
+		return []string{}
+	}
+	derived := derivedGroups[food.FoodGroup] || tags.HasAllergen(model.AllergenLactose) ||
+		tags.HasAllergen(model.AllergenEgg) || containsAnyTerm(padded, nonVeganKeywords)
+	if derived {
+		return []string{model.DietVegetarian}
+	}
+	return []string{model.DietVegan, model.DietVegetarian}
+}
+
+// RuleTags classifica o alimento pelas regras de nome e grupo, usadas para a TACO e para
+// alimentos próprios.
+func RuleTags(food model.Food) *model.FoodTags {
+	allergens := DetectAllergens(food)
+	return &model.FoodTags{
+		Allergens: allergens,
+		Diets:     detectDiets(food, allergens),
+		Source:    model.TagSourceRules,
+	}
+}
+
+// offAllergenTags relaciona as tags allergens_tags do Open Food Facts aos alérgenos do modelo.
+var offAllergenTags = map[string]string{
+	"en:gluten":      model.AllergenGluten,
+	"en:milk":        model.AllergenLactose,
+	"en:nuts":        model.AllergenNuts,
+	"en:peanuts":     model.AllergenNuts,
+	"en:crustaceans": model.AllergenShellfish,
+	"en:molluscs":    model.AllergenShellfish,
+	"en:eggs":        model.AllergenEgg,
+	"en:soybeans":    model.AllergenSoy,
+}
+
+// offTags converte allergens_tags e ingredients_analysis_tags do Open Food Facts. Produtos
+// com análise "maybe" ou desconhecida não recebem dieta. Sem allergens_tags na resposta os
+// alérgenos são desconhecidos e o produto fica sem tags, em vez de parecer livre de alérgenos.
+func offTags(allergenTags, analysisTags []string) *model.FoodTags {
+	if allergenTags == nil {
+		return nil
+	}
+	found := map[string]bool{}
+	for _, tag := range allergenTags {
+		if allergen, ok := offAllergenTags[tag]; ok {
+			found[allergen] = true
+		}
+	}
+	allergens := make([]string, 0, len(found))
+	for allergen := range found {
+		allergens = append(allergens, allergen)
+	}
+	sort.Strings(allergens)
+
+	diets := []string{}
+	for _, tag := range analysisTags {
+		switch tag {
+		case "en:vegan":
+			diets = []string{model.DietVegan, model.DietVegetarian}
+		case "en:vegetarian":
+			if len(diets) == 0 {
+				diets = []string{model.DietVegetarian}
+			}
+		}
+	}
+	return &model.FoodTags{Allergens: allergens, Diets: diets, Source: model.TagSourceOpenFoodFacts}
+}
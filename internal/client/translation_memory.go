@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type InMemoryTranslationRepository struct {
+	mu           sync.RWMutex
+	translations map[string]FoodTranslation
+}
+
+func NewInMemoryTranslationRepository() *InMemoryTranslationRepository {
+	return &InMemoryTranslationRepository{translations: make(map[string]FoodTranslation)}
+}
+
+func translationKey(foodID, l string) string {
+	return foodID + "|" + l
+}
+
+func (r *InMemoryTranslationRepository) ListTranslations(ctx context.Context) ([]FoodTranslation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	translations := make([]FoodTranslation, 0, len(r.translations))
+	for _, t := range r.translations {
+		translations = append(translations, t)
+	}
+	sortTranslations(translations)
+	return translations, nil
+}
+
+func (r *InMemoryTranslationRepository) PutTranslation(ctx context.Context, translation FoodTranslation) (*FoodTranslation, error) {
+	translation, err := normalizeTranslation(translation)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.translations[translationKey(translation.FoodID, translation.Locale)] = translation
+	return &translation, nil
+}
+
+func (r *InMemoryTranslationRepository) DeleteTranslation(ctx context.Context, foodID, l string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := translationKey(foodID, l)
+	if _, ok := r.translations[key]; !ok {
+		return fmt.Errorf("%w: %s (%s)", ErrTranslationNotFound, foodID, l)
+	}
+	delete(r.translations, key)
+	return nil
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"saas-nutri/internal/locale"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var (
+	ErrInvalidTranslation  = errors.New("tradução inválida")
+	ErrTranslationNotFound = errors.New("tradução não encontrada")
+)
+
+// FoodTranslation é o nome de um alimento em outro idioma (chave: food_id + locale). Measures
+// traduz as medidas caseiras pelo nome de exibição em português ("1 unidade média"), guardado
+// normalizado; medidas sem tradução saem em português.
+type FoodTranslation struct {
+	FoodID    string            `json:"food_id" dynamodbav:"food_id"`
+	Locale    string            `json:"locale" dynamodbav:"locale"`
+	Name      string            `json:"name" dynamodbav:"name"`
+	Measures  map[string]string `json:"measures,omitempty" dynamodbav:"measures,omitempty"`
+	UpdatedAt string            `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+type TranslationRepository interface {
+	ListTranslations(ctx context.Context) ([]FoodTranslation, error)
+	PutTranslation(ctx context.Context, translation FoodTranslation) (*FoodTranslation, error)
+	DeleteTranslation(ctx context.Context, foodID, locale string) error
+}
+
+// normalizeTranslation valida o idioma e o nome, normaliza as chaves das medidas e preenche
+// UpdatedAt. Português é o idioma da base e não recebe tradução.
+func normalizeTranslation(translation FoodTranslation) (FoodTranslation, error) {
+	if translation.FoodID == "" {
+		return translation, fmt.Errorf("%w: food_id é obrigatório", ErrInvalidTranslation)
+	}
+	l, ok := locale.Normalize(translation.Locale)
+	if !ok || l == locale.Default {
+		return translation, fmt.Errorf("%w: idioma '%s' não é traduzível (use %s)", ErrInvalidTranslation, translation.Locale, strings.Join(locale.Supported[1:], ", "))
+	}
+	translation.Locale = l
+	translation.Name = strings.TrimSpace(translation.Name)
+	if translation.Name == "" {
+		return translation, fmt.Errorf("%w: name é obrigatório", ErrInvalidTranslation)
+	}
+
+	measures := make(map[string]string, len(translation.Measures))
+	for ptName, name := range translation.Measures {
+		key, name := normalizeString(ptName), strings.TrimSpace(name)
+		if key == "" || name == "" {
+			return translation, fmt.Errorf("%w: medida '%s' sem nome ou sem tradução", ErrInvalidTranslation, ptName)
+		}
+		measures[key] = name
+	}
+	translation.Measures = nil
+	if len(measures) > 0 {
+		translation.Measures = measures
+	}
+	translation.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return translation, nil
+}
+
+func sortTranslations(translations []FoodTranslation) {
+	sort.Slice(translations, func(i, j int) bool {
+		if translations[i].FoodID != translations[j].FoodID {
+			return translations[i].FoodID < translations[j].FoodID
+		}
+		return translations[i].Locale < translations[j].Locale
+	})
+}
+
+type DynamoTranslationRepository struct {
+	DB        *dynamodb.Client
+	TableName string
+}
+
+func NewDynamoTranslationRepository(db *dynamodb.Client, tableName string) *DynamoTranslationRepository {
+	return &DynamoTranslationRepository{DB: db, TableName: tableName}
+}
+
+// ListTranslations lê a tabela inteira: são no máximo alguns milhares de itens por idioma.
+func (r *DynamoTranslationRepository) ListTranslations(ctx context.Context) ([]FoodTranslation, error) {
+	var translations []FoodTranslation
+	paginator := dynamodb.NewScanPaginator(r.DB, &dynamodb.ScanInput{TableName: aws.String(r.TableName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler traduções no DynamoDB: %w", err)
+		}
+		var pageItems []FoodTranslation
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("erro ao fazer unmarshal das traduções: %w", err)
+		}
+		translations = append(translations, pageItems...)
+	}
+	sortTranslations(translations)
+	return translations, nil
+}
+
+func (r *DynamoTranslationRepository) PutTranslation(ctx context.Context, translation FoodTranslation) (*FoodTranslation, error) {
+	translation, err := normalizeTranslation(translation)
+	if err != nil {
+		return nil, err
+	}
+	av, err := attributevalue.MarshalMap(translation)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao fazer marshal da tradução: %w", err)
+	}
+	_, err = r.DB.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.TableName), Item: av})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar tradução no DynamoDB: %w", err)
+	}
+	return &translation, nil
+}
+
+func (r *DynamoTranslationRepository) DeleteTranslation(ctx context.Context, foodID, l string) error {
+	_, err := r.DB.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key: map[string]types.AttributeValue{
+			"food_id": &types.AttributeValueMemberS{Value: foodID},
+			"locale":  &types.AttributeValueMemberS{Value: l},
+		},
+		ConditionExpression: aws.String("attribute_exists(food_id)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: %s (%s)", ErrTranslationNotFound, foodID, l)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao remover tradução no DynamoDB: %w", err)
+	}
+	return nil
+}
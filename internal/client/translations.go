@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"saas-nutri/internal/locale"
+	"saas-nutri/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultTranslationTTL = time.Minute
+
+// builtinMeasureNames traduz a medida padrão "grama", que todo alimento tem.
+var builtinMeasureNames = map[string]map[string]string{
+	defaultMeasureName: {locale.Spanish: "Gramo", locale.English: "Gram"},
+}
+
+// LocalizedNames são os nomes de um idioma, normalizados e indexados por food_id, para a busca.
+// Version muda quando as traduções do idioma mudam e entra na chave do cache de busca.
+type LocalizedNames struct {
+	Locale  string
+	Names   map[string]string
+	Version string
+}
+
+// Translator aplica as traduções de nomes de alimentos e medidas caseiras conforme o idioma da
+// requisição. Como o FoodTagger, mantém as traduções em memória e as relê a cada TTL.
+type Translator struct {
+	repo TranslationRepository
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	byLocale map[string]map[string]FoodTranslation
+	names    map[string]*LocalizedNames
+	loadedAt time.Time
+}
+
+func NewTranslator(repo TranslationRepository, ttl time.Duration) *Translator {
+	if ttl <= 0 {
+		ttl = DefaultTranslationTTL
+	}
+	return &Translator{repo: repo, ttl: ttl}
+}
+
+// load devolve as traduções por idioma e por food_id. Se a leitura falhar, usa a última
+// versão carregada (ou nenhuma tradução).
+func (t *Translator) load(ctx context.Context) (map[string]map[string]FoodTranslation, map[string]*LocalizedNames) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.byLocale != nil && time.Since(t.loadedAt) < t.ttl {
+		return t.byLocale, t.names
+	}
+
+	list, err := t.repo.ListTranslations(ctx)
+	if err != nil {
+		log.Printf("Erro ao carregar traduções, usando versão anterior: %v", err)
+		return t.byLocale, t.names
+	}
+
+	byLocale := map[string]map[string]FoodTranslation{}
+	for _, tr := range list {
+		if byLocale[tr.Locale] == nil {
+			byLocale[tr.Locale] = map[string]FoodTranslation{}
+		}
+		byLocale[tr.Locale][tr.FoodID] = tr
+	}
+	names := make(map[string]*LocalizedNames, len(byLocale))
+	for l, translations := range byLocale {
+		names[l] = buildLocalizedNames(l, translations)
+	}
+	t.byLocale, t.names = byLocale, names
+	t.loadedAt = time.Now()
+	return byLocale, names
+}
+
+func buildLocalizedNames(l string, translations map[string]FoodTranslation) *LocalizedNames {
+	ids := make([]string, 0, len(translations))
+	for id := range translations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	names := make(map[string]string, len(ids))
+	hash := fnv.New64a()
+	for _, id := range ids {
+		names[id] = normalizeString(translations[id].Name)
+		fmt.Fprintf(hash, "%s=%s;", id, names[id])
+	}
+	return &LocalizedNames{Locale: l, Names: names, Version: fmt.Sprintf("%x", hash.Sum64())}
+}
+
+func (t *Translator) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loadedAt = time.Time{}
+}
+
+// Names devolve os nomes traduzidos do idioma para a busca, ou nil para português e para
+// idiomas sem nenhuma tradução.
+func (t *Translator) Names(ctx context.Context, l string) *LocalizedNames {
+	if l == locale.Default {
+		return nil
+	}
+	_, names := t.load(ctx)
+	return names[l]
+}
+
+// Localize troca o nome e as medidas caseiras dos alimentos pelos do idioma. Alimentos sem
+// tradução mantêm o nome da fonte (português na TACO) e ficam sem Locale.
+func (t *Translator) Localize(ctx context.Context, l string, foods ...*model.Food) {
+	if l == locale.Default {
+		return
+	}
+	byLocale, _ := t.load(ctx)
+	for _, food := range foods {
+		tr, ok := byLocale[l][food.Id]
+		if ok {
+			food.Name = tr.Name
+			food.Locale = l
+		}
+		for i := range food.HouseholdMeasures {
+			food.HouseholdMeasures[i].Name = measureTranslation(tr, l, food.HouseholdMeasures[i].Name)
+		}
+	}
+}
+
+// LocalizeMeasures troca o nome de exibição das medidas caseiras. MeasureName continua em
+// português, pois é a chave usada na edição e no cálculo de porções.
+func (t *Translator) LocalizeMeasures(ctx context.Context, l, foodID string, measures []MeasureItem) {
+	if l == locale.Default {
+		return
+	}
+	byLocale, _ := t.load(ctx)
+	tr := byLocale[l][foodID]
+	for i := range measures {
+		measures[i].DisplayName = measureTranslation(tr, l, measures[i].DisplayName)
+	}
+}
+
+func measureTranslation(tr FoodTranslation, l, ptName string) string {
+	key := normalizeString(ptName)
+	if name, ok := tr.Measures[key]; ok {
+		return name
+	}
+	if name, ok := builtinMeasureNames[key][l]; ok {
+		return name
+	}
+	return ptName
+}
+
+func (t *Translator) List(ctx context.Context) ([]FoodTranslation, error) {
+	return t.repo.ListTranslations(ctx)
+}
+
+func (t *Translator) Put(ctx context.Context, translation FoodTranslation) (*FoodTranslation, error) {
+	saved, err := t.repo.PutTranslation(ctx, translation)
+	if err == nil {
+		t.invalidate()
+	}
+	return saved, err
+}
+
+func (t *Translator) Delete(ctx context.Context, foodID, l string) error {
+	err := t.repo.DeleteTranslation(ctx, foodID, l)
+	if err == nil {
+		t.invalidate()
+	}
+	return err
+}
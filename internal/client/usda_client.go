@@ -0,0 +1,304 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"saas-nutri/internal/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	usdaBaseURL      = "https://api.nal.usda.gov/fdc/v1"
+	usdaDemoKey      = "DEMO_KEY"
+	usdaFoodIDPrefix = "usda-"
+	SourceUSDA       = "USDA"
+)
+
+type FoodDetailClient interface {
+	GetFood(ctx context.Context, foodID string) (*model.Food, error)
+}
+
+type USDAClient interface {
+	FoodAPIClient
+	FoodDetailClient
+}
+
+// usdaNutrientNumbers relaciona o número do nutriente na FoodData Central ao campo do model.Food.
+// As unidades da FDC para esses números já coincidem com model.NutrientDefinitions.
+var usdaNutrientNumbers = map[string]string{
+	"307": model.NutrientSodium,
+	"306": model.NutrientPotassium,
+	"301": model.NutrientCalcium,
+	"303": model.NutrientIron,
+	"309": model.NutrientZinc,
+	"304": model.NutrientMagnesium,
+	"305": model.NutrientPhosphorus,
+	"320": model.NutrientVitaminA,
+	"401": model.NutrientVitaminC,
+	"404": model.NutrientVitaminB1,
+	"405": model.NutrientVitaminB2,
+	"415": model.NutrientVitaminB6,
+	"406": model.NutrientVitaminB3,
+	"601": model.NutrientCholesterol,
+	"606": model.NutrientSaturatedFat,
+	"645": model.NutrientMonounsaturatedFat,
+	"646": model.NutrientPolyunsaturatedFat,
+}
+
+const (
+	usdaEnergyKcal     = "208"
+	usdaProtein        = "203"
+	usdaFat            = "204"
+	usdaCarbohydrate   = "205"
+	usdaFiber          = "291"
+	usdaAtwaterGeneral = "957"
+)
+
+type usdaSearchResponse struct {
+	Foods []usdaSearchFood `json:"foods"`
+}
+
+type usdaSearchFood struct {
+	FdcID         int                  `json:"fdcId"`
+	Description   string               `json:"description"`
+	DataType      string               `json:"dataType"`
+	BrandOwner    string               `json:"brandOwner"`
+	FoodNutrients []usdaSearchNutrient `json:"foodNutrients"`
+}
+
+type usdaSearchNutrient struct {
+	NutrientNumber string  `json:"nutrientNumber"`
+	UnitName       string  `json:"unitName"`
+	Value          float64 `json:"value"`
+}
+
+type usdaFoodDetail struct {
+	FdcID                    int                  `json:"fdcId"`
+	Description              string               `json:"description"`
+	DataType                 string               `json:"dataType"`
+	BrandOwner               string               `json:"brandOwner"`
+	FoodNutrients            []usdaDetailNutrient `json:"foodNutrients"`
+	FoodPortions             []usdaFoodPortion    `json:"foodPortions"`
+	ServingSize              float64              `json:"servingSize"`
+	ServingSizeUnit          string               `json:"servingSizeUnit"`
+	HouseholdServingFullText string               `json:"householdServingFullText"`
+}
+
+type usdaDetailNutrient struct {
+	Nutrient struct {
+		Number   string `json:"number"`
+		UnitName string `json:"unitName"`
+	} `json:"nutrient"`
+	Amount *float64 `json:"amount"`
+}
+
+type usdaFoodPortion struct {
+	Amount             float64 `json:"amount"`
+	GramWeight         float64 `json:"gramWeight"`
+	Modifier           string  `json:"modifier"`
+	PortionDescription string  `json:"portionDescription"`
+	MeasureUnit        struct {
+		Name string `json:"name"`
+	} `json:"measureUnit"`
+}
+
+type usdaClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+// NewUSDAClient cria o cliente da FoodData Central. Sem chave, usa a DEMO_KEY pública,
+// que tem limite de requisições baixo e serve apenas para desenvolvimento.
+func NewUSDAClient(apiKey string) USDAClient {
+	return NewUSDAClientWithBaseURL(apiKey, usdaBaseURL)
+}
+
+func NewUSDAClientWithBaseURL(apiKey, baseURL string) USDAClient {
+	if apiKey == "" {
+		apiKey = usdaDemoKey
+	}
+	return &usdaClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+	}
+}
+
+func IsUSDAFoodID(foodID string) bool {
+	return strings.HasPrefix(foodID, usdaFoodIDPrefix)
+}
+
+// get envia a chave no header X-Api-Key, e não no parâmetro api_key, para que ela não apareça
+// na URL que o net/http inclui nos erros de rede (e, portanto, nos logs).
+func (c *usdaClient) get(ctx context.Context, path string, params url.Values, target interface{}) error {
+	apiURL := c.baseURL + path
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		log.Printf("Erro ao criar request para USDA: %v", err)
+		return fmt.Errorf("erro interno ao preparar busca")
+	}
+	req.Header.Set("User-Agent", "SaaS Nutri MVP - Golang Client - v0.1")
+	req.Header.Set("X-Api-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Printf("Erro ao fazer request para USDA: %v", err)
+		return fmt.Errorf("erro ao conectar com API da USDA")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrProductNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Erro status code da USDA API: %d", resp.StatusCode)
+		return fmt.Errorf("API da USDA retornou erro %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		log.Printf("Erro ao decodificar JSON da USDA: %v", err)
+		return fmt.Errorf("erro ao ler resposta da API da USDA")
+	}
+	return nil
+}
+
+func (c *usdaClient) SearchFoods(ctx context.Context, query string) ([]model.Food, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("pageSize", "10")
+	params.Set("dataType", "Foundation,SR Legacy,Survey (FNDDS),Branded")
+
+	var searchResp usdaSearchResponse
+	if err := c.get(ctx, "/foods/search", params, &searchResp); err != nil {
+		return nil, err
+	}
+
+	foods := make([]model.Food, 0, len(searchResp.Foods))
+	for _, f := range searchResp.Foods {
+		values := make(map[string]float64, len(f.FoodNutrients))
+		for _, n := range f.FoodNutrients {
+			if strings.EqualFold(n.UnitName, "kJ") {
+				continue
+			}
+			values[n.NutrientNumber] = n.Value
+		}
+		food := usdaFood(f.FdcID, f.Description, f.BrandOwner, values)
+		foods = append(foods, food)
+	}
+	return foods, nil
+}
+
+func (c *usdaClient) GetFood(ctx context.Context, foodID string) (*model.Food, error) {
+	fdcID := strings.TrimPrefix(foodID, usdaFoodIDPrefix)
+	if _, err := strconv.Atoi(fdcID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+
+	var detail usdaFoodDetail
+	err := c.get(ctx, "/food/"+fdcID, url.Values{}, &detail)
+	if errors.Is(err, ErrProductNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrFoodNotFound, foodID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64, len(detail.FoodNutrients))
+	for _, n := range detail.FoodNutrients {
+		if n.Amount == nil || strings.EqualFold(n.Nutrient.UnitName, "kJ") {
+			continue
+		}
+		values[n.Nutrient.Number] = *n.Amount
+	}
+
+	food := usdaFood(detail.FdcID, detail.Description, detail.BrandOwner, values)
+	food.HouseholdMeasures = append([]model.HouseholdMeasure{model.GramMeasure()}, usdaPortions(detail)...)
+	return &food, nil
+}
+
+// usdaFood monta o model.Food a partir dos valores por 100 g indexados pelo número do nutriente.
+func usdaFood(fdcID int, description, brand string, values map[string]float64) model.Food {
+	name := description
+	if brand != "" {
+		name = fmt.Sprintf("%s (%s)", description, brand)
+	}
+
+	energy, ok := values[usdaEnergyKcal]
+	if !ok {
+		// Itens Foundation às vezes trazem só a energia calculada por Atwater.
+		energy = values[usdaAtwaterGeneral]
+	}
+
+	food := model.Food{
+		Id:            usdaFoodIDPrefix + strconv.Itoa(fdcID),
+		Name:          name,
+		Source:        SourceUSDA,
+		EnergyKcal:    energy,
+		ProteinG:      values[usdaProtein],
+		CarbohydrateG: values[usdaCarbohydrate],
+		FatG:          values[usdaFat],
+		FiberG:        values[usdaFiber],
+	}
+
+	nutrients := make(map[string]model.NutrientValue)
+	for number, key := range usdaNutrientNumbers {
+		if value, ok := values[number]; ok {
+			nutrients[key] = model.MeasuredNutrient(key, value)
+		}
+	}
+	if len(nutrients) > 0 {
+		food.Nutrients = nutrients
+	}
+	return food
+}
+
+// usdaPortions converte foodPortions (Foundation, SR Legacy, FNDDS) e a porção declarada de
+// itens Branded em medidas caseiras.
+func usdaPortions(detail usdaFoodDetail) []model.HouseholdMeasure {
+	var measures []model.HouseholdMeasure
+	for _, p := range detail.FoodPortions {
+		if p.GramWeight <= 0 {
+			continue
+		}
+		name := strings.TrimSpace(p.PortionDescription)
+		if name == "" || strings.EqualFold(name, "Quantity not specified") {
+			unit := p.MeasureUnit.Name
+			if unit == "" || strings.EqualFold(unit, "undetermined") {
+				unit = p.Modifier
+			} else if p.Modifier != "" {
+				unit = unit + ", " + p.Modifier
+			}
+			if unit == "" {
+				continue
+			}
+			amount := p.Amount
+			if amount <= 0 {
+				amount = 1
+			}
+			name = strconv.FormatFloat(amount, 'f', -1, 64) + " " + unit
+		}
+		measures = append(measures, model.HouseholdMeasure{Name: name, Grams: p.GramWeight})
+	}
+
+	unit := strings.ToLower(detail.ServingSizeUnit)
+	if detail.ServingSize > 0 && (unit == "g" || unit == "grm" || unit == "ml" || unit == "mlt") {
+		name := "1 porção"
+		if text := strings.TrimSpace(detail.HouseholdServingFullText); text != "" {
+			name = "1 porção: " + text
+		}
+		measures = append(measures, model.HouseholdMeasure{Name: name, Grams: detail.ServingSize})
+	}
+	return measures
+}
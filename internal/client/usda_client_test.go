@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+const usdaTestKey = "chave-de-teste"
+
+// newFDCStandIn sobe um servidor local no lugar da FoodData Central, servindo as respostas
+// gravadas em testdata/: usda_search_<query>.json e usda_food_<fdcId>.json.
+func newFDCStandIn(t *testing.T) USDAClient {
+	t.Helper()
+	serve := func(w http.ResponseWriter, r *http.Request, fixture string) {
+		if got := r.Header.Get("X-Api-Key"); got != usdaTestKey {
+			t.Errorf("X-Api-Key = %q, esperava a chave configurada", got)
+		}
+		if r.URL.Query().Has("api_key") {
+			t.Errorf("a chave não deve ir na query: %s", r.URL.RawQuery)
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if errors.Is(err, os.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			t.Fatalf("lendo fixture %s: %v", fixture, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /foods/search", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("pageSize"); got != "10" {
+			t.Errorf("pageSize = %q, esperava 10", got)
+		}
+		serve(w, r, "usda_search_"+r.URL.Query().Get("query")+".json")
+	})
+	mux.HandleFunc("GET /food/{id}", func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, "usda_food_"+r.PathValue("id")+".json")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return NewUSDAClientWithBaseURL(usdaTestKey, server.URL)
+}
+
+func TestUSDASearchFoods(t *testing.T) {
+	foods, err := newFDCStandIn(t).SearchFoods(context.Background(), "rice")
+	if err != nil {
+		t.Fatalf("SearchFoods: %v", err)
+	}
+	if len(foods) != 2 {
+		t.Fatalf("esperava 2 alimentos, veio %d", len(foods))
+	}
+
+	rice := foods[0]
+	if rice.Id != "usda-168878" || rice.Source != SourceUSDA || rice.Name != "Rice, white, long-grain, regular, enriched, cooked" {
+		t.Errorf("identificação inesperada: %+v", rice)
+	}
+	// A energia em kJ (268) vem depois da em kcal e não pode sobrescrevê-la.
+	if rice.EnergyKcal != 130 || rice.ProteinG != 2.69 || rice.CarbohydrateG != 28.2 || rice.FatG != 0.28 || rice.FiberG != 0.4 {
+		t.Errorf("macronutrientes inesperados: %+v", rice)
+	}
+	if iron := rice.Nutrients[model.NutrientIron]; iron.Value == nil || *iron.Value != 1.2 || iron.Unit != "mg" {
+		t.Errorf("ferro inesperado: %+v", iron)
+	}
+
+	branded := foods[1]
+	if branded.Name != "PARBOILED RICE (Acme Foods)" {
+		t.Errorf("item Branded deveria trazer a marca no nome, veio %q", branded.Name)
+	}
+	if len(rice.HouseholdMeasures) != 0 {
+		t.Errorf("a busca não traz porções, veio %+v", rice.HouseholdMeasures)
+	}
+}
+
+func TestUSDAGetFood(t *testing.T) {
+	food, err := newFDCStandIn(t).GetFood(context.Background(), "usda-2346404")
+	if err != nil {
+		t.Fatalf("GetFood: %v", err)
+	}
+	// Item Foundation sem o 208: a energia vem do fator de Atwater (957), nunca do kJ.
+	if food.EnergyKcal != 148 {
+		t.Errorf("energia = %v, esperava 148 kcal do Atwater", food.EnergyKcal)
+	}
+
+	want := []model.HouseholdMeasure{
+		{Name: "Grama", Grams: 1},
+		{Name: "1 large", Grams: 50.3},
+		{Name: "1 cup", Grams: 243},
+	}
+	if len(food.HouseholdMeasures) != len(want) {
+		t.Fatalf("medidas = %+v, esperava %+v", food.HouseholdMeasures, want)
+	}
+	for i, m := range want {
+		got := food.HouseholdMeasures[i]
+		if got.Name != m.Name || got.Grams != m.Grams {
+			t.Errorf("medida %d = %+v, esperava %+v", i, got, m)
+		}
+	}
+}
+
+func TestUSDAGetFoodBrandedServing(t *testing.T) {
+	food, err := newFDCStandIn(t).GetFood(context.Background(), "usda-2345678")
+	if err != nil {
+		t.Fatalf("GetFood: %v", err)
+	}
+	last := food.HouseholdMeasures[len(food.HouseholdMeasures)-1]
+	if last.Name != "1 porção: 1/4 cup" || last.Grams != 45 {
+		t.Errorf("porção declarada inesperada: %+v", last)
+	}
+}
+
+// TestUSDANutrientMapping confere, contra a resposta gravada, que cada número da FDC mapeado
+// cai no nutriente certo e que a unidade da FDC coincide com a de model.NutrientDefinitions.
+func TestUSDANutrientMapping(t *testing.T) {
+	food, err := newFDCStandIn(t).GetFood(context.Background(), "usda-2346404")
+	if err != nil {
+		t.Fatalf("GetFood: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join("testdata", "usda_food_2346404.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var detail usdaFoodDetail
+	if err := json.Unmarshal(body, &detail); err != nil {
+		t.Fatal(err)
+	}
+
+	fdcUnits := map[string]string{"mg": "mg", "µg": "mcg", "ug": "mcg", "g": "g"}
+	for _, n := range detail.FoodNutrients {
+		key, ok := usdaNutrientNumbers[n.Nutrient.Number]
+		if !ok || n.Amount == nil {
+			continue
+		}
+		if unit := fdcUnits[strings.ToLower(n.Nutrient.UnitName)]; unit != model.NutrientUnit(key) {
+			t.Errorf("nutriente %s (%s): unidade da FDC %q difere de %q", n.Nutrient.Number, key, n.Nutrient.UnitName, model.NutrientUnit(key))
+		}
+		got := food.Nutrients[key]
+		if got.Value == nil || *got.Value != *n.Amount {
+			t.Errorf("nutriente %s (%s) = %+v, esperava %v", n.Nutrient.Number, key, got, *n.Amount)
+		}
+	}
+	for number, key := range usdaNutrientNumbers {
+		if _, ok := food.Nutrients[key]; !ok {
+			t.Errorf("nutriente %s (%s) ausente do alimento", number, key)
+		}
+	}
+}
+
+func TestUSDAGetFoodNotFound(t *testing.T) {
+	client := newFDCStandIn(t)
+	for _, id := range []string{"usda-999", "usda-abc"} {
+		if _, err := client.GetFood(context.Background(), id); !errors.Is(err, ErrFoodNotFound) {
+			t.Errorf("GetFood(%q) = %v, esperava ErrFoodNotFound", id, err)
+		}
+	}
+}
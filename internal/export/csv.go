@@ -0,0 +1,45 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"saas-nutri/internal/model"
+)
+
+// utf8BOM faz o Excel abrir o CSV como UTF-8, preservando os acentos.
+const utf8BOM = "\ufeff"
+
+type csvWriter struct {
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{csv: csv.NewWriter(w)}
+	if err := cw.csv.Write(header()); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (w *csvWriter) WriteFoods(foods []model.Food) error {
+	for _, food := range foods {
+		cells := row(food)
+		record := make([]string, len(cells))
+		for i, c := range cells {
+			record[i] = c.String()
+		}
+		if err := w.csv.Write(record); err != nil {
+			return err
+		}
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,130 @@
+// Package export serializa a tabela de alimentos para uso offline em planilhas e auditorias.
+// Os writers recebem os alimentos aos poucos e escrevem direto na saída, sem acumular a base.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"saas-nutri/internal/model"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatXLSX  = "xlsx"
+	FormatJSONL = "jsonl"
+)
+
+var ErrUnknownFormat = errors.New("formato de exportação desconhecido")
+
+// Writer recebe os alimentos página a página. Close completa o arquivo (no xlsx, sem Close
+// o arquivo fica inválido) e não fecha a saída.
+type Writer interface {
+	WriteFoods(foods []model.Food) error
+	Close() error
+}
+
+type format struct {
+	contentType string
+	newWriter   func(io.Writer) (Writer, error)
+}
+
+var formats = map[string]format{
+	FormatCSV:   {contentType: "text/csv; charset=utf-8", newWriter: newCSVWriter},
+	FormatXLSX:  {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", newWriter: newXLSXWriter},
+	FormatJSONL: {contentType: "application/x-ndjson", newWriter: newJSONLWriter},
+}
+
+func IsFormat(name string) bool {
+	_, ok := formats[name]
+	return ok
+}
+
+func ContentType(name string) string {
+	return formats[name].contentType
+}
+
+func NewWriter(name string, w io.Writer) (Writer, error) {
+	f, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+	return f.newWriter(w)
+}
+
+// cell é uma célula da planilha: número quando isNumber, texto caso contrário.
+type cell struct {
+	text     string
+	number   float64
+	isNumber bool
+}
+
+func textCell(s string) cell {
+	return cell{text: s}
+}
+
+func numberCell(v float64) cell {
+	return cell{number: v, isNumber: true}
+}
+
+func (c cell) String() string {
+	if c.isNumber {
+		return strconv.FormatFloat(c.number, 'f', -1, 64)
+	}
+	return c.text
+}
+
+// header devolve os títulos das colunas do CSV e do xlsx: identificação, macronutrientes,
+// micronutrientes de model.NutrientDefinitions, tags e medidas caseiras.
+func header() []string {
+	columns := []string{"ID", "Nome", "Fonte", "Grupo", "Energia (kcal)", "Proteína (g)", "Carboidrato (g)", "Lipídios (g)", "Fibra alimentar (g)"}
+	for _, def := range model.NutrientDefinitions {
+		columns = append(columns, fmt.Sprintf("%s (%s)", def.Name, def.Unit))
+	}
+	return append(columns, "Alérgenos", "Dietas", "Medidas caseiras")
+}
+
+// row monta a linha de um alimento na ordem de header. Nutrientes seguem a notação da TACO:
+// "Tr" para traço e "NA" para não analisado; nutriente ausente fica vazio.
+func row(food model.Food) []cell {
+	cells := []cell{
+		textCell(food.Id),
+		textCell(food.Name),
+		textCell(food.Source),
+		textCell(food.FoodGroup),
+		numberCell(food.EnergyKcal),
+		numberCell(food.ProteinG),
+		numberCell(food.CarbohydrateG),
+		numberCell(food.FatG),
+		numberCell(food.FiberG),
+	}
+	for _, def := range model.NutrientDefinitions {
+		n, ok := food.Nutrients[def.Key]
+		switch {
+		case !ok:
+			cells = append(cells, textCell(""))
+		case n.Value != nil:
+			cells = append(cells, numberCell(*n.Value))
+		case n.Status == model.NutrientStatusTrace:
+			cells = append(cells, textCell("Tr"))
+		default:
+			cells = append(cells, textCell("NA"))
+		}
+	}
+
+	var allergens, diets string
+	if food.Tags != nil {
+		allergens = strings.Join(food.Tags.Allergens, ", ")
+		diets = strings.Join(food.Tags.Diets, ", ")
+	}
+	var measures []string
+	for _, m := range food.HouseholdMeasures {
+		if m.IsDefault {
+			continue
+		}
+		measures = append(measures, fmt.Sprintf("%s (%s g)", m.Name, strconv.FormatFloat(m.Grams, 'f', -1, 64)))
+	}
+	return append(cells, textCell(allergens), textCell(diets), textCell(strings.Join(measures, "; ")))
+}
@@ -0,0 +1,267 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+// testFoods cobre os casos que exigem escape: &, <, >, aspas, vírgula, quebra de linha e
+// nomes com acentos e fora do ASCII.
+func testFoods() []model.Food {
+	sodium := 3.5
+	return []model.Food{
+		{
+			Id:         "taco-3",
+			Name:       "Arroz, tipo 1, cozido",
+			Source:     "TACO",
+			FoodGroup:  "cereais",
+			EnergyKcal: 128, ProteinG: 2.5, CarbohydrateG: 28.1, FatG: 0.2, FiberG: 1.6,
+			Nutrients: map[string]model.NutrientValue{
+				model.NutrientSodium:  {Value: &sodium, Unit: "mg", Status: model.NutrientStatusMeasured},
+				model.NutrientIron:    {Unit: "mg", Status: model.NutrientStatusTrace},
+				model.NutrientCalcium: {Unit: "mg", Status: model.NutrientStatusNotAnalyzed},
+			},
+			Tags: &model.FoodTags{Allergens: []string{}, Diets: []string{model.DietVegan, model.DietVegetarian}},
+			HouseholdMeasures: []model.HouseholdMeasure{
+				model.GramMeasure(),
+				{Name: "1 colher de sopa cheia", Grams: 25},
+			},
+		},
+		{
+			Id:     "custom-1",
+			Name:   "Pão & <Queijo> \"mineiro\"\nda Vovó — 日本",
+			Source: "CUSTOM",
+			Tags:   &model.FoodTags{Allergens: []string{model.AllergenGluten, model.AllergenLactose}, Diets: []string{model.DietVegetarian}},
+		},
+	}
+}
+
+func writeAll(t *testing.T, format string, foods []model.Food) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter(%s): %v", format, err)
+	}
+	// Duas páginas, como na exportação.
+	for _, food := range foods {
+		if err := w.WriteFoods([]model.Food{food}); err != nil {
+			t.Fatalf("WriteFoods: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// column devolve o índice da coluna de título title.
+func column(t *testing.T, title string) int {
+	t.Helper()
+	for i, h := range header() {
+		if h == title {
+			return i
+		}
+	}
+	t.Fatalf("coluna %q ausente do cabeçalho", title)
+	return -1
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := NewWriter("ods", io.Discard); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("esperava ErrUnknownFormat, veio %v", err)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := writeAll(t, FormatCSV, testFoods())
+	if !bytes.HasPrefix(out, []byte(utf8BOM)) {
+		t.Fatalf("CSV deveria começar com o BOM do UTF-8")
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(out[len(utf8BOM):])).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV inválido: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("esperava cabeçalho e 2 linhas, veio %d", len(records))
+	}
+	if !reflect.DeepEqual(records[0], header()) {
+		t.Errorf("cabeçalho inesperado: %v", records[0])
+	}
+
+	rice, custom := records[1], records[2]
+	checks := []struct {
+		record []string
+		title  string
+		want   string
+	}{
+		{rice, "Nome", "Arroz, tipo 1, cozido"},
+		{rice, "Energia (kcal)", "128"},
+		{rice, "Carboidrato (g)", "28.1"},
+		{rice, "Sódio (mg)", "3.5"},
+		{rice, "Ferro (mg)", "Tr"},
+		{rice, "Cálcio (mg)", "NA"},
+		{rice, "Zinco (mg)", ""},
+		{rice, "Dietas", "vegan, vegetarian"},
+		{rice, "Medidas caseiras", "1 colher de sopa cheia (25 g)"},
+		{custom, "Nome", "Pão & <Queijo> \"mineiro\"\nda Vovó — 日本"},
+		{custom, "Alérgenos", "gluten, lactose"},
+	}
+	for _, c := range checks {
+		if got := c.record[column(t, c.title)]; got != c.want {
+			t.Errorf("%s = %q; esperava %q", c.title, got, c.want)
+		}
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	foods := testFoods()
+	out := writeAll(t, FormatJSONL, foods)
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	var lines int
+	for scanner.Scan() {
+		var food model.Food
+		if err := json.Unmarshal(scanner.Bytes(), &food); err != nil {
+			t.Fatalf("linha %d não é JSON válido: %v", lines+1, err)
+		}
+		want := foods[lines]
+		if food.Id != want.Id || food.Name != want.Name || !reflect.DeepEqual(food.Tags, want.Tags) {
+			t.Errorf("linha %d = %+v; esperava %+v", lines+1, food, want)
+		}
+		lines++
+	}
+	if lines != len(foods) {
+		t.Errorf("esperava %d linhas, veio %d", len(foods), lines)
+	}
+}
+
+// xlsxSheet é o suficiente de SpreadsheetML para ler as células de volta.
+type xlsxSheet struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXWriter(t *testing.T) {
+	out := writeAll(t, FormatXLSX, testFoods())
+
+	archive, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("xlsx não é um zip válido: %v", err)
+	}
+	parts := map[string]*zip.File{}
+	for _, f := range archive.File {
+		parts[f.Name] = f
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		f, ok := parts[name]
+		if !ok {
+			t.Errorf("parte %s ausente", name)
+			continue
+		}
+		// Toda parte precisa ser XML bem formado.
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("abrir %s: %v", name, err)
+		}
+		decoder := xml.NewDecoder(rc)
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("%s não é XML válido: %v", name, err)
+				break
+			}
+		}
+		rc.Close()
+	}
+
+	rc, err := parts["xl/worksheets/sheet1.xml"].Open()
+	if err != nil {
+		t.Fatalf("abrir planilha: %v", err)
+	}
+	defer rc.Close()
+	var sheet xlsxSheet
+	if err := xml.NewDecoder(rc).Decode(&sheet); err != nil {
+		t.Fatalf("decodificar planilha: %v", err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("esperava cabeçalho e 2 linhas, veio %d", len(sheet.Rows))
+	}
+
+	cellAt := func(row int, title string) (ref, typ, value string) {
+		t.Helper()
+		ref = columnName(column(t, title)) + sheet.Rows[row].R
+		for _, c := range sheet.Rows[row].Cells {
+			if c.Ref == ref {
+				if c.Type == "inlineStr" {
+					return c.Ref, c.Type, c.Inline
+				}
+				return c.Ref, c.Type, c.Value
+			}
+		}
+		return ref, "", ""
+	}
+
+	if _, _, got := cellAt(0, "Alérgenos"); got != "Alérgenos" {
+		t.Errorf("cabeçalho com acento = %q", got)
+	}
+	if _, typ, got := cellAt(1, "Energia (kcal)"); typ != "" || got != "128" {
+		t.Errorf("energia deveria ser célula numérica 128, veio %q (%q)", got, typ)
+	}
+	if _, typ, got := cellAt(1, "Ferro (mg)"); typ != "inlineStr" || got != "Tr" {
+		t.Errorf("traço deveria ser texto Tr, veio %q (%q)", got, typ)
+	}
+	if ref, _, got := cellAt(1, "Zinco (mg)"); got != "" {
+		t.Errorf("nutriente ausente deveria deixar %s vazia, veio %q", ref, got)
+	}
+	if _, typ, got := cellAt(2, "Nome"); typ != "inlineStr" || got != "Pão & <Queijo> \"mineiro\"\nda Vovó — 日本" {
+		t.Errorf("nome com escape e não ASCII = %q (%q)", got, typ)
+	}
+
+	if sheetXML := readPart(t, parts["xl/worksheets/sheet1.xml"]); !strings.Contains(sheetXML, "Pão &amp; &lt;Queijo&gt;") {
+		t.Errorf("&, < e > deveriam estar escapados no XML da planilha")
+	}
+}
+
+func readPart(t *testing.T, f *zip.File) string {
+	t.Helper()
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatalf("abrir %s: %v", f.Name, err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ler %s: %v", f.Name, err)
+	}
+	return string(b)
+}
+
+func TestColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, want := range cases {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %q; esperava %q", index, got, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"saas-nutri/internal/model"
+)
+
+// jsonlWriter escreve um model.Food por linha, no mesmo formato da API.
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) (Writer, error) {
+	return &jsonlWriter{encoder: json.NewEncoder(w)}, nil
+}
+
+func (w *jsonlWriter) WriteFoods(foods []model.Food) error {
+	for _, food := range foods {
+		if err := w.encoder.Encode(food); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
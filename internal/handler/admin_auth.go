@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AdminAuth protege as rotas de curadoria pelo header "X-Admin-Key". O header é separado de
+// Authorization para não conflitar com a autenticação por tenant.
+type AdminAuth struct {
+	keyHash [sha256.Size]byte
+	enabled bool
+}
+
+// NewAdminAuth recebe a chave administrativa. Sem chave, as rotas administrativas ficam desativadas.
+func NewAdminAuth(apiKey string) *AdminAuth {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return &AdminAuth{}
+	}
+	return &AdminAuth{keyHash: sha256.Sum256([]byte(apiKey)), enabled: true}
+}
+
+func (a *AdminAuth) Enabled() bool {
+	return a.enabled
+}
+
+func (a *AdminAuth) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.enabled {
+			RespondWithError(w, http.StatusForbidden, "API administrativa desativada")
+			return
+		}
+		key := strings.TrimSpace(r.Header.Get("X-Admin-Key"))
+		hash := sha256.Sum256([]byte(key))
+		if key == "" || subtle.ConstantTimeCompare(hash[:], a.keyHash[:]) != 1 {
+			RespondWithError(w, http.StatusUnauthorized, "Chave administrativa inválida")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
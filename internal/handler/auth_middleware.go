@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"saas-nutri/internal/tenant"
+)
+
+// TenantAuth autentica a clínica pelo header "Authorization: Bearer <chave>". As chaves
+// ficam em memória apenas como hash SHA-256.
+type TenantAuth struct {
+	tenantsByKeyHash map[[sha256.Size]byte]string
+}
+
+// NewTenantAuth recebe as chaves no formato "chave1:tenant1,chave2:tenant2".
+func NewTenantAuth(apiKeys string) (*TenantAuth, error) {
+	auth := &TenantAuth{tenantsByKeyHash: make(map[[sha256.Size]byte]string)}
+	for _, entry := range strings.Split(apiKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, tenantID, found := strings.Cut(entry, ":")
+		if !found || key == "" || tenantID == "" {
+			return nil, fmt.Errorf("entrada de chave de API inválida: esperado 'chave:tenant'")
+		}
+		auth.tenantsByKeyHash[sha256.Sum256([]byte(key))] = tenantID
+	}
+	return auth, nil
+}
+
+func (a *TenantAuth) TenantCount() int {
+	return len(a.tenantsByKeyHash)
+}
+
+// authenticate devolve o tenant da requisição; present indica se o header foi enviado.
+func (a *TenantAuth) authenticate(r *http.Request) (tenantID string, present bool) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", false
+	}
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found {
+		return "", true
+	}
+	return a.tenantsByKeyHash[sha256.Sum256([]byte(strings.TrimSpace(token)))], true
+}
+
+// OptionalTenant identifica o tenant quando há credencial, sem exigir autenticação.
+// Credenciais inválidas continuam sendo rejeitadas.
+func (a *TenantAuth) OptionalTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, present := a.authenticate(r)
+		if !present {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if tenantID == "" {
+			RespondWithError(w, http.StatusUnauthorized, "Credencial inválida")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), tenantID)))
+	})
+}
+
+// RequireTenant exige um tenant já identificado por OptionalTenant.
+func (a *TenantAuth) RequireTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := tenant.FromContext(r.Context()); !ok {
+			RespondWithError(w, http.StatusUnauthorized, "Autenticação obrigatória")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"saas-nutri/internal/client"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type BarcodeHandler struct {
+	products client.BarcodeClient
+}
+
+func NewBarcodeHandler(products client.BarcodeClient) *BarcodeHandler {
+	return &BarcodeHandler{
+		products: products,
+	}
+}
+
+// GetFoodByBarcode godoc
+// @Summary      Busca alimento por código de barras
+// @Description  Valida o código EAN/GTIN e busca o produto na Open Food Facts. Valores por 100 g; a porção da embalagem vira medida caseira.
+// @Tags         alimentos
+// @Accept       json
+// @Produce      json
+// @Param        ean path string true "Código de barras EAN/GTIN (8, 12, 13 ou 14 dígitos)" example(7891000100103)
+// @Success      200 {object} model.Food "Produto encontrado"
+// @Failure      400 {object} string "Erro: código de barras inválido"
+// @Failure      404 {object} string "Produto não encontrado"
+// @Failure      502 {object} string "Erro ao consultar a Open Food Facts"
+// @Failure      503 {object} string "Open Food Facts indisponível (limite de requisições ou circuito aberto); ver header Retry-After"
+// @Router       /foods/barcode/{ean} [get]
+
+func (h *BarcodeHandler) GetFoodByBarcode(w http.ResponseWriter, r *http.Request) {
+	ean, err := client.NormalizeGTIN(chi.URLParam(r, "ean"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Código de barras inválido (verifique os dígitos e o dígito verificador)")
+		return
+	}
+
+	food, err := h.products.GetProductByBarcode(r.Context(), ean)
+	if errors.Is(err, client.ErrProductNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Produto não encontrado")
+		return
+	}
+	if errors.Is(err, client.ErrUpstreamUnavailable) {
+		var upstreamErr *client.UpstreamError
+		if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(upstreamErr.RetryAfter.Seconds()))))
+		}
+		RespondWithError(w, http.StatusServiceUnavailable, "Base de produtos indisponível no momento, tente novamente em instantes")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusBadGateway, "Erro ao consultar a base de produtos")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, food)
+}
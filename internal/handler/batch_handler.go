@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+)
+
+type batchFoodsRequest struct {
+	IDs []string `json:"ids" example:"taco-1,taco-3"`
+}
+
+// GetFoodsBatch godoc
+// @Summary      Busca vários alimentos de uma vez
+// @Description  Devolve os alimentos com suas medidas caseiras na ordem pedida (ids repetidos aparecem uma vez). Ids inexistentes vêm em missing; falhas de origem externa, em errors. Aceita até 100 ids.
+// @Tags         alimentos
+// @Accept       json
+// @Produce      json
+// @Param        ids body batchFoodsRequest true "Ids dos alimentos"
+// @Success      200 {object} model.BatchFoodsResponse "Alimentos encontrados e ids ausentes"
+// @Failure      400 {object} string "Lista de ids vazia ou acima do limite"
+// @Failure      500 {object} string "Erro interno ao buscar alimentos"
+// @Router       /foods/batch [post]
+
+func (h *FoodHandler) GetFoodsBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchFoodsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+	ids := make([]string, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		RespondWithError(w, http.StatusBadRequest, "Campo 'ids' é obrigatório")
+		return
+	}
+	if len(ids) > client.MaxBatchFoods {
+		RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Campo 'ids' aceita no máximo %d ids", client.MaxBatchFoods))
+		return
+	}
+
+	foods, missing, failures, err := h.resolver.GetFoods(r.Context(), ids)
+	if err != nil {
+		log.Printf("Erro ao buscar lote de %d alimentos: %v", len(ids), err)
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimentos")
+		return
+	}
+
+	response := model.BatchFoodsResponse{
+		Foods:   make([]model.Food, 0, len(foods)),
+		Missing: []string{},
+	}
+	for _, id := range ids {
+		if food, ok := foods[id]; ok {
+			response.Foods = append(response.Foods, *food)
+		}
+	}
+	response.Missing = append(response.Missing, missing...)
+	for _, id := range ids {
+		if err, ok := failures[id]; ok {
+			log.Printf("Erro ao buscar alimento %s no lote: %v", id, err)
+			response.Errors = append(response.Errors, model.BatchFoodError{ID: id, Message: "Erro ao consultar a origem do alimento"})
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
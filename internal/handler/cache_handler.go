@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"saas-nutri/internal/client"
+)
+
+type CacheHandler struct {
+	cache *client.CachingFoodRepository
+}
+
+func NewCacheHandler(cache *client.CachingFoodRepository) *CacheHandler {
+	return &CacheHandler{cache: cache}
+}
+
+// GetCacheStats godoc
+// @Summary      Estatísticas do cache de alimentos
+// @Description  Entradas, acertos, faltas, descartes por tamanho e expirações de cada cache (foods, measures, searches) desta instância.
+// @Tags         curadoria
+// @Produce      json
+// @Security     AdminKey
+// @Success      200 {array} client.CacheStats "Estatísticas por cache"
+// @Router       /admin/cache [get]
+
+func (h *CacheHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, h.cache.Stats())
+}
+
+// PurgeCache godoc
+// @Summary      Esvazia o cache de alimentos
+// @Description  Remove todas as entradas e zera as estatísticas desta instância.
+// @Tags         curadoria
+// @Security     AdminKey
+// @Success      204 "Cache esvaziado"
+// @Router       /admin/cache [delete]
+
+func (h *CacheHandler) PurgeCache(w http.ResponseWriter, r *http.Request) {
+	h.cache.Purge()
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/nutrition"
+)
+
+const (
+	minCompareFoods = 2
+	maxCompareFoods = 5
+)
+
+// CompareFoods godoc
+// @Summary      Compara alimentos lado a lado
+// @Description  Alinha os nutrientes de 2 a 5 alimentos na base escolhida: 100g (padrão), kcal (porção de 100 kcal) ou measure (medida caseira padrão de cada alimento, ou 100 g quando a padrão é a grama). Em cada nutriente com sentido definido (better higher ou lower), marca o melhor e o pior valor; diff_percent é a diferença em relação ao primeiro id.
+// @Tags         alimentos
+// @Produce      json
+// @Param        ids query string true "Ids separados por vírgula (2 a 5)" example(taco-1,taco-3)
+// @Param        basis query string false "Base de comparação: 100g, kcal ou measure"
+// @Success      200 {object} model.FoodComparison "Tabela de nutrientes alinhada"
+// @Failure      400 {object} string "Ids ou base inválidos, ou alimento sem energia na base kcal"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Failure      500 {object} string "Erro interno ao buscar alimentos"
+// @Router       /foods/compare [get]
+
+func (h *FoodHandler) CompareFoods(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	basis := params.Get("basis")
+	switch basis {
+	case "":
+		basis = model.CompareBasis100g
+	case model.CompareBasis100g, model.CompareBasisKcal, model.CompareBasisMeasure:
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'basis' deve ser '100g', 'kcal' ou 'measure'")
+		return
+	}
+
+	seen := map[string]bool{}
+	var ids []string
+	for _, id := range strings.Split(params.Get("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) < minCompareFoods || len(ids) > maxCompareFoods {
+		RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parâmetro 'ids' deve ter de %d a %d ids distintos", minCompareFoods, maxCompareFoods))
+		return
+	}
+
+	ctx := r.Context()
+	foods := make([]model.Food, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			food, err := h.resolver.GetFood(ctx, id)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			foods[i] = *food
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if errors.Is(err, client.ErrFoodNotFound) {
+			RespondWithError(w, http.StatusNotFound, fmt.Sprintf("Alimento '%s' não encontrado", ids[i]))
+			return
+		}
+		if err != nil {
+			log.Printf("Erro ao buscar alimento %s para comparação: %v", ids[i], err)
+			RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimentos")
+			return
+		}
+	}
+
+	for _, food := range foods {
+		if _, _, ok := nutrition.CompareBasisGrams(food, basis); !ok {
+			RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Alimento '%s' sem valor energético; não é possível comparar por kcal", food.Id))
+			return
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, nutrition.CompareFoods(foods, basis))
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/tenant"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CustomFoodHandler struct {
+	repo client.CustomFoodRepository
+}
+
+func NewCustomFoodHandler(repo client.CustomFoodRepository) *CustomFoodHandler {
+	return &CustomFoodHandler{
+		repo: repo,
+	}
+}
+
+func respondWithCustomFoodError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, client.ErrInvalidFood):
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, client.ErrFoodNotFound):
+		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+	default:
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao processar alimento próprio")
+	}
+}
+
+// CreateCustomFood godoc
+// @Summary      Cadastra alimento próprio
+// @Description  Cadastra um alimento visível apenas para a clínica autenticada. Valores por 100 g.
+// @Tags         alimentos-proprios
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        food body model.Food true "Alimento (id e source são ignorados)"
+// @Success      201 {object} model.Food "Alimento criado"
+// @Failure      400 {object} string "Dados inválidos"
+// @Failure      401 {object} string "Autenticação obrigatória"
+// @Router       /custom-foods [post]
+
+func (h *CustomFoodHandler) CreateCustomFood(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	var food model.Food
+	if err := json.NewDecoder(r.Body).Decode(&food); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	created, err := h.repo.CreateCustomFood(r.Context(), tenantID, food)
+	if err != nil {
+		respondWithCustomFoodError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, created)
+}
+
+// ListCustomFoods godoc
+// @Summary      Lista alimentos próprios
+// @Tags         alimentos-proprios
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} model.Food "Alimentos da clínica"
+// @Failure      401 {object} string "Autenticação obrigatória"
+// @Router       /custom-foods [get]
+
+func (h *CustomFoodHandler) ListCustomFoods(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	foods, err := h.repo.ListCustomFoods(r.Context(), tenantID)
+	if err != nil {
+		respondWithCustomFoodError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, foods)
+}
+
+// GetCustomFood godoc
+// @Summary      Busca alimento próprio
+// @Tags         alimentos-proprios
+// @Produce      json
+// @Security     BearerAuth
+// @Param        foodId path string true "ID do alimento próprio"
+// @Success      200 {object} model.Food "Alimento"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Router       /custom-foods/{foodId} [get]
+
+func (h *CustomFoodHandler) GetCustomFood(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	food, err := h.repo.GetCustomFood(r.Context(), tenantID, chi.URLParam(r, "foodId"))
+	if err != nil {
+		respondWithCustomFoodError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, food)
+}
+
+// UpdateCustomFood godoc
+// @Summary      Edita alimento próprio
+// @Tags         alimentos-proprios
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        foodId path string true "ID do alimento próprio"
+// @Param        food body model.Food true "Alimento"
+// @Success      200 {object} model.Food "Alimento atualizado"
+// @Failure      400 {object} string "Dados inválidos"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Router       /custom-foods/{foodId} [put]
+
+func (h *CustomFoodHandler) UpdateCustomFood(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	var food model.Food
+	if err := json.NewDecoder(r.Body).Decode(&food); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	updated, err := h.repo.UpdateCustomFood(r.Context(), tenantID, chi.URLParam(r, "foodId"), food)
+	if err != nil {
+		respondWithCustomFoodError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCustomFood godoc
+// @Summary      Remove alimento próprio
+// @Tags         alimentos-proprios
+// @Security     BearerAuth
+// @Param        foodId path string true "ID do alimento próprio"
+// @Success      204 "Alimento removido"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Router       /custom-foods/{foodId} [delete]
+
+func (h *CustomFoodHandler) DeleteCustomFood(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	if err := h.repo.DeleteCustomFood(r.Context(), tenantID, chi.URLParam(r, "foodId")); err != nil {
+		respondWithCustomFoodError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/export"
+	"saas-nutri/internal/model"
+)
+
+// ExportFoods godoc
+// @Summary      Exporta a tabela de alimentos
+// @Description  Baixa todos os alimentos TACO com nutrientes por 100 g, tags e medidas caseiras, para uso offline. O arquivo é gerado em streaming, lendo a base página a página. Aceita os mesmos filtros da busca; com search, exporta só os resultados da busca, em ordem de relevância. No CSV e no xlsx, "Tr" indica traço e "NA" não analisado; o jsonl traz um model.Food por linha.
+// @Tags         alimentos
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
+// @Param        format query string false "Formato do arquivo: csv (padrão), xlsx ou jsonl"
+// @Param        search query string false "Termo de busca (opcional)" example(arroz)
+// @Param        group query string false "Slug do grupo de alimentos (ver GET /food-groups)" example(carnes)
+// @Param        exclude_allergens query string false "Alérgenos a excluir, separados por vírgula (gluten, lactose, nuts, shellfish, egg, soy)" example(gluten,lactose)
+// @Param        diet query string false "Dieta exigida (vegan ou vegetarian)" example(vegetarian)
+// @Success      200 {file} file "Arquivo com os alimentos"
+// @Failure      400 {object} string "Formato ou filtros inválidos"
+// @Failure      500 {object} string "Erro interno ao exportar alimentos"
+// @Router       /foods/export [get]
+
+func (h *FoodHandler) ExportFoods(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	format := params.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if !export.IsFormat(format) {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'format' deve ser 'csv', 'xlsx' ou 'jsonl'")
+		return
+	}
+
+	filter, ok := h.parseSearchFilter(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	search := params.Get("search")
+	if search != "" {
+		filter.Synonyms = h.synonyms.Synonyms(ctx)
+	}
+
+	// Os headers só são enviados com a primeira página: até lá, um erro ainda vira 500.
+	var writer export.Writer
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", export.ContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="alimentos.%s"`, format))
+		w.WriteHeader(http.StatusOK)
+		var err error
+		writer, err = export.NewWriter(format, w)
+		return err
+	}
+
+	exported := 0
+	err := client.ExportFoods(ctx, h.tacoRepo, search, filter, func(foods []model.Food) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		h.resolver.ApplyTags(ctx, foods)
+		h.resolver.Localize(ctx, foods)
+		if err := writer.WriteFoods(foods); err != nil {
+			return err
+		}
+		exported += len(foods)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			log.Printf("Erro ao iniciar exportação de alimentos: %v", err)
+			RespondWithError(w, http.StatusInternalServerError, "Erro interno ao exportar alimentos")
+			return
+		}
+		// O arquivo fica incompleto de propósito (no xlsx, inválido) para não passar por completo.
+		log.Printf("Exportação de alimentos interrompida após %d itens: %v", exported, err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Erro ao finalizar exportação de alimentos: %v", err)
+		return
+	}
+	log.Printf("Exportação %s concluída com %d alimentos", format, exported)
+}
@@ -1,46 +1,60 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"saas-nutri/internal/client"
+	"saas-nutri/internal/locale"
 	"saas-nutri/internal/model"
+	"saas-nutri/internal/tenant"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/go-chi/chi/v5"
 )
 
 type FoodHandler struct {
-	tacoRepo *client.TacoRepository
+	tacoRepo      client.FoodRepository
+	resolver      *client.FoodResolver
+	cursors       *client.CursorCodec
+	federated     *client.FederatedSearcher
+	nutrientIndex *client.NutrientIndex
+	synonyms      *client.SynonymDictionary
 }
 
-func NewFoodHandler(taco *client.TacoRepository) *FoodHandler {
+func NewFoodHandler(taco client.FoodRepository, resolver *client.FoodResolver, cursors *client.CursorCodec, federated *client.FederatedSearcher, nutrientIndex *client.NutrientIndex, synonyms *client.SynonymDictionary) *FoodHandler {
 	return &FoodHandler{
-		tacoRepo: taco,
-	}
-}
-
-func mapTacoToFood(tacoItem client.TacoFoodItem) model.Food {
-	return model.Food{
-		Id:            tacoItem.FoodID,
-		Name:          tacoItem.OriginalName,
-		Source:        "TACO",
-		EnergyKcal:    tacoItem.EnergyKcal,
-		ProteinG:      tacoItem.ProteinG,
-		CarbohydrateG: tacoItem.CarbohydrateG,
-		FatG:          tacoItem.FatG,
-		FiberG:        tacoItem.FiberG,
+		tacoRepo:      taco,
+		resolver:      resolver,
+		cursors:       cursors,
+		federated:     federated,
+		nutrientIndex: nutrientIndex,
+		synonyms:      synonyms,
 	}
 }
 
 // SearchFoods godoc
 // @Summary      Busca alimentos
-// @Description  Busca alimentos na base TACO
+// @Description  Busca alimentos na base TACO ignorando acentos, em qualquer ordem de palavras e tolerando pequenos erros de digitação e reconhecendo nomes regionais (ex: aipim e macaxeira encontram mandioca; o sinônimo usado aparece em matched_synonym). Em espanhol ou inglês, busca pelos nomes traduzidos e, nos alimentos sem tradução, pelo nome em português. Resultados ordenados por relevância. Com autenticação, os alimentos próprios (source CUSTOM) e as receitas (source RECIPE) da clínica vêm antes dos da TACO; cada página tem no máximo limit itens e o cursor do header Link continua de onde a página parou, passando dos itens da clínica para a TACO.
 // @Tags         alimentos
 // @Accept       json
 // @Produce      json
 // @Param        search query string true "Termo para buscar o alimento" example(arroz)
+// @Param        limit query int false "Quantidade máxima de itens por página (1 a 100, padrão 25)"
+// @Param        cursor query string false "Cursor opaco da próxima página, obtido do header Link"
+// @Param        group query string false "Slug do grupo de alimentos (ver GET /food-groups)" example(carnes)
+// @Param        exclude_allergens query string false "Alérgenos a excluir, separados por vírgula (gluten, lactose, nuts, shellfish, egg, soy)" example(gluten,lactose)
+// @Param        diet query string false "Dieta exigida (vegan ou vegetarian)" example(vegetarian)
+// @Param        lang query string false "Idioma da busca e dos nomes (pt, es ou en); na falta, usa Accept-Language" example(es)
+// @Param        sources query string false "Fontes separadas por vírgula (taco, off, usda, custom, recipes). Quando informado, a resposta é um model.FederatedSearchResponse" example(taco,off)
 // @Success      200 {array} model.Food "Lista de alimentos encontrados da TACO"
-// @Failure      400 {object} string "Erro: Parâmetro 'search' é obrigatório"
+// @Header       200 {string} Link "Link rel=\"next\" para a próxima página, quando houver"
+// @Failure      400 {object} string "Erro: Parâmetro 'search' é obrigatório, ou limit/cursor inválidos"
 // @Failure      500 {object} string "Erro interno ao buscar dados dos alimentos"
 // @Router       /foods [get]
 
@@ -51,32 +65,271 @@ func (h *FoodHandler) SearchFoods(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := h.parseSearchFilter(w, r)
+	if !ok {
+		return
+	}
+	filter.Synonyms = h.synonyms.Synonyms(r.Context())
+
+	if rawSources := r.URL.Query().Get("sources"); rawSources != "" {
+		h.searchFederated(w, r, searchTerm, strings.Split(rawSources, ","), filter, limit)
+		return
+	}
+
+	startKey, ok := h.parseCursor(w, r)
+	if !ok {
+		return
+	}
+
+	// A lista paginada é a dos alimentos próprios e receitas da clínica seguida da TACO. Enquanto
+	// a página ainda cobre os itens da clínica, o cursor guarda só a posição neles
+	// (tenant_offset); depois, é o cursor da TACO.
 	ctx := r.Context()
-	var mappedResults []model.Food
+	mappedResults := []model.Food{}
+	tenantOffset, inTenantItems, ok := tenantCursorOffset(w, startKey)
+	if !ok {
+		return
+	}
+	if tenantID, hasTenant := tenant.FromContext(ctx); hasTenant && inTenantItems {
+		tenantResults := h.searchTenantFoods(ctx, tenantID, searchTerm, filter)
+		if tenantOffset > len(tenantResults) {
+			tenantOffset = len(tenantResults)
+		}
+		end := min(tenantOffset+limit, len(tenantResults))
+		mappedResults = append(mappedResults, tenantResults[tenantOffset:end]...)
+		if end < len(tenantResults) {
+			h.setNextLink(w, r, limit, tenantCursor(end))
+			h.respondWithFoods(w, r, mappedResults)
+			return
+		}
+	}
+	if inTenantItems {
+		startKey = nil
+	}
 
-	tacoResults, errTaco := h.tacoRepo.SearchFoodsByNamePrefix(ctx, searchTerm)
+	tacoLimit := limit - len(mappedResults)
+	if tacoLimit == 0 {
+		// A página fechou exatamente no fim dos itens da clínica: basta saber se a TACO tem algo.
+		tacoLimit = 1
+	}
+	tacoPage, errTaco := h.tacoRepo.SearchFoodsByNamePrefix(ctx, searchTerm, filter, tacoLimit, startKey)
+	if errors.Is(errTaco, client.ErrInvalidCursor) {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'cursor' não corresponde a esta busca")
+		return
+	}
 	if errTaco != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar dados dos alimentos")
 		return
 	}
 
-	for _, tacoItem := range tacoResults {
-		mappedTacoItem := mapTacoToFood(tacoItem)
+	if len(mappedResults) == limit {
+		if len(tacoPage.Items) > 0 {
+			h.setNextLink(w, r, limit, tenantCursor(tenantOffset+limit))
+		}
+		h.respondWithFoods(w, r, mappedResults)
+		return
+	}
+
+	for _, tacoItem := range tacoPage.Items {
+		mappedTacoItem := client.MapTacoToFood(tacoItem)
 		mappedResults = append(mappedResults, mappedTacoItem)
 	}
 
-	RespondWithJSON(w, http.StatusOK, mappedResults)
+	h.setNextLink(w, r, limit, tacoPage.LastEvaluatedKey)
+	h.respondWithFoods(w, r, mappedResults)
+}
+
+func (h *FoodHandler) respondWithFoods(w http.ResponseWriter, r *http.Request, foods []model.Food) {
+	h.resolver.ApplyTags(r.Context(), foods)
+	h.resolver.Localize(r.Context(), foods)
+	RespondWithJSON(w, http.StatusOK, foods)
+}
+
+// searchTenantFoods devolve os alimentos próprios seguidos das receitas da clínica que casam com
+// a busca, até MaxSearchLimit de cada. Falhas de uma das fontes só são registradas no log.
+func (h *FoodHandler) searchTenantFoods(ctx context.Context, tenantID, searchTerm string, filter client.SearchFilter) []model.Food {
+	var results []model.Food
+
+	customResults, err := h.resolver.CustomFoods.SearchCustomFoods(ctx, tenantID, searchTerm, client.MaxSearchLimit)
+	if err != nil {
+		log.Printf("Erro ao buscar alimentos próprios do tenant %s, seguindo apenas com TACO: %v", tenantID, err)
+	} else {
+		results = append(results, filter.FilterFoods(customResults)...)
+	}
+
+	recipeResults, err := h.resolver.Recipes.SearchRecipes(ctx, tenantID, searchTerm, client.MaxSearchLimit)
+	if err != nil {
+		log.Printf("Erro ao buscar receitas do tenant %s, seguindo sem receitas: %v", tenantID, err)
+	} else {
+		results = append(results, filter.FilterFoods(recipeResults)...)
+	}
+	return results
+}
+
+const tenantOffsetCursorKey = "tenant_offset"
+
+func tenantCursor(offset int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		tenantOffsetCursorKey: &types.AttributeValueMemberS{Value: strconv.Itoa(offset)},
+	}
+}
+
+// tenantCursorOffset diz se a página ainda começa nos itens da clínica (sem cursor ou com
+// cursor tenant_offset) e a partir de qual posição.
+func tenantCursorOffset(w http.ResponseWriter, startKey map[string]types.AttributeValue) (int, bool, bool) {
+	if startKey == nil {
+		return 0, true, true
+	}
+	raw, ok := startKey[tenantOffsetCursorKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return 0, false, true
+	}
+	offset, err := strconv.Atoi(raw.Value)
+	if err != nil || offset < 0 {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'cursor' inválido")
+		return 0, false, false
+	}
+	return offset, true, true
+}
+
+// parseSearchFilter lê os filtros group, exclude_allergens e diet comuns às buscas.
+func (h *FoodHandler) parseSearchFilter(w http.ResponseWriter, r *http.Request) (client.SearchFilter, bool) {
+	params := r.URL.Query()
+	filter := client.SearchFilter{FoodGroup: params.Get("group"), Diet: params.Get("diet")}
+	if filter.FoodGroup != "" && !model.IsFoodGroup(filter.FoodGroup) {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'group' inválido. Consulte GET /api/food-groups")
+		return filter, false
+	}
+	if filter.Diet != "" && !model.IsDiet(filter.Diet) {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'diet' deve ser 'vegan' ou 'vegetarian'")
+		return filter, false
+	}
+	if rawAllergens := params.Get("exclude_allergens"); rawAllergens != "" {
+		for _, allergen := range strings.Split(rawAllergens, ",") {
+			allergen = strings.TrimSpace(allergen)
+			if !model.IsAllergen(allergen) {
+				RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Alérgeno '%s' desconhecido", allergen))
+				return filter, false
+			}
+			filter.ExcludeAllergens = append(filter.ExcludeAllergens, allergen)
+		}
+	}
+	if len(filter.ExcludeAllergens) > 0 || filter.Diet != "" {
+		filter.TagOverrides = h.resolver.TagOverrides(r.Context())
+	}
+	filter.Translations = h.resolver.LocalizedNames(r.Context())
+	return filter, true
+}
+
+func parseLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	rawLimit := r.URL.Query().Get("limit")
+	if rawLimit == "" {
+		return client.DefaultSearchLimit, true
+	}
+	parsed, err := strconv.Atoi(rawLimit)
+	if err != nil || parsed < 1 || parsed > client.MaxSearchLimit {
+		RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parâmetro 'limit' deve ser um número entre 1 e %d", client.MaxSearchLimit))
+		return 0, false
+	}
+	return parsed, true
+}
+
+func (h *FoodHandler) parseCursor(w http.ResponseWriter, r *http.Request) (map[string]types.AttributeValue, bool) {
+	rawCursor := r.URL.Query().Get("cursor")
+	if rawCursor == "" {
+		return nil, true
+	}
+	decoded, err := h.cursors.Decode(rawCursor, cursorScope(r))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'cursor' inválido ou de outra busca")
+		return nil, false
+	}
+	return decoded, true
+}
+
+// cursorScope identifica a busca de r para o cursor: rota, parâmetros (menos cursor e limit,
+// que podem mudar entre páginas), tenant e idioma.
+func cursorScope(r *http.Request) string {
+	params := r.URL.Query()
+	params.Del("cursor")
+	params.Del("limit")
+	tenantID, _ := tenant.FromContext(r.Context())
+	return client.CursorScope(r.URL.Path, params.Encode(), tenantID, locale.FromContext(r.Context()))
+}
+
+// setNextLink publica o header Link rel="next" quando há mais páginas.
+func (h *FoodHandler) setNextLink(w http.ResponseWriter, r *http.Request, limit int, lastEvaluatedKey map[string]types.AttributeValue) {
+	if lastEvaluatedKey == nil {
+		return
+	}
+	nextCursor, err := h.cursors.Encode(lastEvaluatedKey, cursorScope(r))
+	if err != nil {
+		log.Printf("Erro ao gerar cursor da próxima página: %v", err)
+		return
+	}
+	nextQuery := r.URL.Query()
+	nextQuery.Set("limit", strconv.Itoa(limit))
+	nextQuery.Set("cursor", nextCursor)
+	w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, nextQuery.Encode()))
+}
+
+func (h *FoodHandler) searchFederated(w http.ResponseWriter, r *http.Request, searchTerm string, sources []string, filter client.SearchFilter, limit int) {
+	foods, statuses, err := h.federated.Search(r.Context(), searchTerm, sources, filter, limit)
+	if errors.Is(err, client.ErrUnknownSource) {
+		RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parâmetro 'sources' inválido. Fontes disponíveis: %s", strings.Join(h.federated.SourceNames(), ", ")))
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar dados dos alimentos")
+		return
+	}
+
+	failed := 0
+	for _, status := range statuses {
+		if status.Status != client.SourceStatusOK {
+			failed++
+		}
+	}
+	if failed == len(statuses) {
+		RespondWithError(w, http.StatusBadGateway, "Nenhuma fonte de alimentos respondeu")
+		return
+	}
+
+	h.resolver.ApplyTags(r.Context(), foods)
+	RespondWithJSON(w, http.StatusOK, model.FederatedSearchResponse{
+		Results: foods,
+		Sources: statuses,
+		Partial: failed > 0,
+	})
+}
+
+// ListFoodGroups godoc
+// @Summary      Lista os grupos de alimentos
+// @Description  Retorna a taxonomia de grupos da TACO. O slug é usado no filtro group= da busca.
+// @Tags         alimentos
+// @Produce      json
+// @Success      200 {array} model.FoodGroup "Grupos de alimentos"
+// @Router       /food-groups [get]
+
+func (h *FoodHandler) ListFoodGroups(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, model.FoodGroups)
 }
 
 // GetFoodMeasures godoc
 // @Summary      Busca medidas caseiras de um alimento
-// @Description  Retorna uma lista de medidas caseiras e seus equivalentes em gramas para um ID de alimento específico.
+// @Description  Retorna uma lista de medidas caseiras e seus equivalentes em gramas para um ID de alimento específico: TACO, USDA, alimento próprio ou receita.
 // @Tags         alimentos
 // @Accept       json
 // @Produce      json
 // @Param        foodId path string true "ID do Alimento (ex: UUID ou código TACO)"
 // @Success      200 {array} client.MeasureItem "Lista de medidas caseiras"
 // @Failure      400 {object} string "Erro: ID do alimento é obrigatório"
+// @Failure      404 {object} string "Alimento não encontrado"
 // @Failure      500 {object} string "Erro interno ao buscar medidas"
 // @Router       /foods/{foodId}/measures [get]
 
@@ -88,7 +341,11 @@ func (h *FoodHandler) GetFoodMeasures(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	measures, err := h.tacoRepo.GetMeasuresForFood(ctx, foodId)
+	measures, err := h.resolver.GetMeasures(ctx, foodId)
+	if errors.Is(err, client.ErrFoodNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+		return
+	}
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Erro ao buscar medidas caseiras")
 		return
@@ -105,11 +362,15 @@ func (h *FoodHandler) GetFoodWithMeasures(w http.ResponseWriter, r *http.Request
 	}
 
 	ctx := r.Context()
-	food, err := h.tacoRepo.GetFoodWithMeasures(ctx, foodId)
-	if err != nil {
+	food, err := h.resolver.GetFood(ctx, foodId)
+	if errors.Is(err, client.ErrFoodNotFound) {
 		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
 		return
 	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimento")
+		return
+	}
 
 	RespondWithJSON(w, http.StatusOK, food)
 }
\ No newline at end of file
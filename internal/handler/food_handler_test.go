@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/tenant"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const testTenantHeader = "X-Test-Tenant"
+
+type testFoodHandler struct {
+	handler *FoodHandler
+	router  http.Handler
+}
+
+// newTestFoodHandler monta o FoodHandler sobre os dados de exemplo em memória e as rotas de
+// /api/foods como em cmd/server. O header X-Test-Tenant faz as vezes da autenticação.
+func newTestFoodHandler(t *testing.T) *testFoodHandler {
+	t.Helper()
+	foods, measures := client.SampleTacoFoods()
+	foodRepo := client.NewInMemoryFoodRepository(foods, measures)
+	resolver := client.NewFoodResolver(foodRepo, client.NewInMemoryCustomFoodRepository(), client.NewInMemoryRecipeRepository())
+	cursors, err := client.NewCursorCodec("segredo-de-teste")
+	if err != nil {
+		t.Fatalf("NewCursorCodec: %v", err)
+	}
+	nutrientIndex := client.NewNutrientIndex(client.NewTacoView(foodRepo.ListFoods))
+	synonyms := client.NewSynonymDictionary(client.NewInMemorySynonymRepository(), time.Minute)
+	h := NewFoodHandler(foodRepo, resolver, cursors, client.NewFederatedSearcher(), nutrientIndex, synonyms)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tenantID := r.Header.Get(testTenantHeader); tenantID != "" {
+				r = r.WithContext(tenant.WithTenant(r.Context(), tenantID))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Route("/api/foods", func(r chi.Router) {
+		r.Get("/", h.SearchFoods)
+		r.Get("/query", h.QueryFoods)
+		r.Get("/compare", h.CompareFoods)
+		r.Get("/{foodId}", h.GetFoodWithMeasures)
+		r.Get("/{foodId}/measures", h.GetFoodMeasures)
+		r.Post("/{foodId}/portion", h.CalculateFoodPortion)
+		r.Get("/{foodId}/substitutes", h.GetFoodSubstitutes)
+	})
+	return &testFoodHandler{handler: h, router: r}
+}
+
+func (th *testFoodHandler) do(t *testing.T, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	th.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeJSON[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("resposta não é JSON válido (%v): %s", err, rec.Body.String())
+	}
+	return v
+}
+
+func foodIDs(foods []model.Food) []string {
+	ids := make([]string, 0, len(foods))
+	for _, f := range foods {
+		ids = append(ids, f.Id)
+	}
+	return ids
+}
+
+// nextLink extrai a URL do header Link rel="next", ou "" se não houver.
+func nextLink(rec *httptest.ResponseRecorder) string {
+	link := rec.Header().Get("Link")
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return link[start+1 : end]
+}
+
+func TestSearchFoodsBadInput(t *testing.T) {
+	th := newTestFoodHandler(t)
+	cases := map[string]string{
+		"sem search":            "/api/foods/",
+		"limit zero":            "/api/foods/?search=arroz&limit=0",
+		"limit acima":           "/api/foods/?search=arroz&limit=101",
+		"limit não numérico":    "/api/foods/?search=arroz&limit=dez",
+		"grupo desconhecido":    "/api/foods/?search=arroz&group=doces-finos",
+		"dieta desconhecida":    "/api/foods/?search=arroz&diet=keto",
+		"alérgeno desconhecido": "/api/foods/?search=arroz&exclude_allergens=gluten,pólen",
+		"cursor adulterado":     "/api/foods/?search=arroz&cursor=abc",
+	}
+	for name, target := range cases {
+		t.Run(name, func(t *testing.T) {
+			if rec := th.do(t, http.MethodGet, target, ""); rec.Code != http.StatusBadRequest {
+				t.Errorf("GET %s = %d; esperava 400: %s", target, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSearchFoodsRanksAndFilters(t *testing.T) {
+	th := newTestFoodHandler(t)
+	cases := []struct {
+		target string
+		want   []string
+	}{
+		{target: "/api/foods/?search=arroz", want: []string{"taco-1", "taco-3"}},
+		{target: "/api/foods/?search=feijao%20preto", want: []string{"taco-567"}},
+		{target: "/api/foods/?search=maca", want: []string{"taco-182"}},
+		{target: "/api/foods/?search=arroz&group=frutas", want: []string{}},
+		{target: "/api/foods/?search=macarrao", want: []string{}},
+	}
+	for _, c := range cases {
+		rec := th.do(t, http.MethodGet, c.target, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", c.target, rec.Code, rec.Body.String())
+		}
+		got := foodIDs(decodeJSON[[]model.Food](t, rec))
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("GET %s = %v; esperava %v", c.target, got, c.want)
+		}
+	}
+}
+
+func TestSearchFoodsPagination(t *testing.T) {
+	th := newTestFoodHandler(t)
+
+	first := th.do(t, http.MethodGet, "/api/foods/?search=arroz&limit=1", "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("primeira página = %d: %s", first.Code, first.Body.String())
+	}
+	if got := foodIDs(decodeJSON[[]model.Food](t, first)); len(got) != 1 || got[0] != "taco-1" {
+		t.Fatalf("primeira página = %v; esperava [taco-1]", got)
+	}
+	next := nextLink(first)
+	if next == "" {
+		t.Fatalf("primeira página deveria ter Link rel=next, veio %q", first.Header().Get("Link"))
+	}
+
+	second := th.do(t, http.MethodGet, next, "")
+	if second.Code != http.StatusOK {
+		t.Fatalf("segunda página = %d: %s", second.Code, second.Body.String())
+	}
+	if got := foodIDs(decodeJSON[[]model.Food](t, second)); len(got) != 1 || got[0] != "taco-3" {
+		t.Errorf("segunda página = %v; esperava [taco-3]", got)
+	}
+	if link := second.Header().Get("Link"); link != "" {
+		t.Errorf("última página não deveria ter Link, veio %q", link)
+	}
+
+	// O cursor é da busca por arroz e não serve para outra busca.
+	parsed, err := url.Parse(next)
+	if err != nil {
+		t.Fatalf("Link inválido: %v", err)
+	}
+	replay := "/api/foods/?search=feijao&cursor=" + url.QueryEscape(parsed.Query().Get("cursor"))
+	if rec := th.do(t, http.MethodGet, replay, ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("cursor reaproveitado em outra busca = %d; esperava 400", rec.Code)
+	}
+}
+
+func TestSearchFoodsTenantItemsComeFirst(t *testing.T) {
+	th := newTestFoodHandler(t)
+	custom, err := th.handler.resolver.CustomFoods.CreateCustomFood(context.Background(), "clinica-1", model.Food{Name: "Arroz da casa", EnergyKcal: 130})
+	if err != nil {
+		t.Fatalf("CreateCustomFood: %v", err)
+	}
+
+	rec := th.do(t, http.MethodGet, "/api/foods/?search=arroz", "", testTenantHeader, "clinica-1")
+	if got := foodIDs(decodeJSON[[]model.Food](t, rec)); strings.Join(got, ",") != custom.Id+",taco-1,taco-3" {
+		t.Errorf("com tenant = %v; esperava o alimento próprio antes da TACO", got)
+	}
+
+	rec = th.do(t, http.MethodGet, "/api/foods/?search=arroz", "", testTenantHeader, "clinica-2")
+	if got := foodIDs(decodeJSON[[]model.Food](t, rec)); strings.Join(got, ",") != "taco-1,taco-3" {
+		t.Errorf("outro tenant não deveria ver o alimento próprio: %v", got)
+	}
+}
+
+func TestGetFoodWithMeasures(t *testing.T) {
+	th := newTestFoodHandler(t)
+
+	rec := th.do(t, http.MethodGet, "/api/foods/taco-3", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET taco-3 = %d: %s", rec.Code, rec.Body.String())
+	}
+	food := decodeJSON[model.Food](t, rec)
+	if food.Id != "taco-3" || food.Name != "Arroz, tipo 1, cozido" || food.EnergyKcal != 128 {
+		t.Errorf("alimento inesperado: %+v", food)
+	}
+	if len(food.HouseholdMeasures) != 2 || food.HouseholdMeasures[1].Name != "1 colher de sopa cheia" || food.HouseholdMeasures[1].Grams != 25 {
+		t.Errorf("medidas inesperadas: %+v", food.HouseholdMeasures)
+	}
+
+	for _, target := range []string{"/api/foods/taco-9999", "/api/foods/custom-nao-existe", "/api/foods/recipe-nao-existe"} {
+		if rec := th.do(t, http.MethodGet, target, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d; esperava 404", target, rec.Code)
+		}
+	}
+
+	// Sem o parâmetro de rota (handler chamado fora do roteador), o ID é obrigatório.
+	rec = httptest.NewRecorder()
+	th.handler.GetFoodWithMeasures(rec, httptest.NewRequest(http.MethodGet, "/api/foods/", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("sem foodId = %d; esperava 400", rec.Code)
+	}
+}
+
+func TestGetFoodMeasures(t *testing.T) {
+	th := newTestFoodHandler(t)
+
+	rec := th.do(t, http.MethodGet, "/api/foods/taco-561/measures", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET medidas de taco-561 = %d: %s", rec.Code, rec.Body.String())
+	}
+	measures := decodeJSON[[]client.MeasureItem](t, rec)
+	if len(measures) != 2 || measures[0].MeasureName != "grama" || measures[1].DisplayName != "1 concha média" || measures[1].GramEquivalent != 86 {
+		t.Errorf("medidas inesperadas: %+v", measures)
+	}
+
+	if rec := th.do(t, http.MethodGet, "/api/foods/custom-nao-existe/measures", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("medidas de alimento próprio inexistente = %d; esperava 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	th.handler.GetFoodMeasures(rec, httptest.NewRequest(http.MethodGet, "/api/foods//measures", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("sem foodId = %d; esperava 400", rec.Code)
+	}
+}
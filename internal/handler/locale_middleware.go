@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"saas-nutri/internal/locale"
+)
+
+// DetectLocale identifica o idioma da resposta pelo parâmetro lang ou, na falta dele, pelo
+// header Accept-Language. Nomes de alimentos e de medidas sem tradução saem em português.
+func DetectLocale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := locale.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+		if rawLang := r.URL.Query().Get("lang"); rawLang != "" {
+			parsed, ok := locale.Normalize(rawLang)
+			if !ok {
+				RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parâmetro 'lang' deve ser um destes idiomas: %s", strings.Join(locale.Supported, ", ")))
+				return
+			}
+			l = parsed
+		}
+
+		w.Header().Set("Content-Language", l)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(locale.WithLocale(r.Context(), l)))
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"saas-nutri/internal/client"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type measureRequest struct {
+	MeasureName     string  `json:"measure_name" example:"colher de sopa cheia"`
+	MeasureQuantity string  `json:"measure_quantity" example:"1"`
+	GramEquivalent  float64 `json:"gram_equivalent" example:"25"`
+}
+
+func (m measureRequest) toMeasureItem(foodID string) client.MeasureItem {
+	return client.MeasureItem{
+		FoodID:          foodID,
+		MeasureName:     strings.TrimSpace(m.MeasureName),
+		MeasureQuantity: strings.TrimSpace(m.MeasureQuantity),
+		GramEquivalent:  m.GramEquivalent,
+	}
+}
+
+func respondWithMeasureError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, client.ErrInvalidMeasure):
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, client.ErrFoodNotFound):
+		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+	case errors.Is(err, client.ErrMeasureNotFound):
+		RespondWithError(w, http.StatusNotFound, "Medida caseira não encontrada")
+	case errors.Is(err, client.ErrMeasureExists):
+		RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao salvar medida caseira")
+	}
+}
+
+// CreateFoodMeasure godoc
+// @Summary      Cadastra medida caseira
+// @Description  Cadastra uma medida caseira para o alimento. Exige a chave administrativa. O equivalente em gramas deve ser positivo e de até 2000 g, e o nome não pode repetir outra medida do alimento.
+// @Tags         medidas
+// @Accept       json
+// @Produce      json
+// @Param        foodId path string true "ID do Alimento"
+// @Security     AdminKey
+// @Param        measure body measureRequest true "Medida caseira"
+// @Success      201 {object} client.MeasureItem "Medida criada"
+// @Failure      400 {object} string "Dados inválidos"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Failure      409 {object} string "Medida já cadastrada"
+// @Router       /foods/{foodId}/measures [post]
+
+func (h *FoodHandler) CreateFoodMeasure(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+
+	var req measureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	measure, err := h.tacoRepo.CreateMeasure(r.Context(), req.toMeasureItem(foodId))
+	if err != nil {
+		respondWithMeasureError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, measure)
+}
+
+// UpdateFoodMeasure godoc
+// @Summary      Edita medida caseira
+// @Description  Edita quantidade e equivalente em gramas de uma medida. Informar outro measure_name renomeia a medida. Exige a chave administrativa.
+// @Tags         medidas
+// @Accept       json
+// @Produce      json
+// @Param        foodId path string true "ID do Alimento"
+// @Security     AdminKey
+// @Param        measureName path string true "Nome atual da medida"
+// @Param        measure body measureRequest true "Medida caseira"
+// @Success      200 {object} client.MeasureItem "Medida atualizada"
+// @Failure      400 {object} string "Dados inválidos"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Failure      404 {object} string "Alimento ou medida não encontrados"
+// @Failure      409 {object} string "Já existe medida com o novo nome"
+// @Router       /foods/{foodId}/measures/{measureName} [put]
+
+func (h *FoodHandler) UpdateFoodMeasure(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+	measureName := chi.URLParam(r, "measureName")
+
+	var req measureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	measure, err := h.tacoRepo.UpdateMeasure(r.Context(), foodId, measureName, req.toMeasureItem(foodId))
+	if err != nil {
+		respondWithMeasureError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, measure)
+}
+
+// DeleteFoodMeasure godoc
+// @Summary      Remove medida caseira
+// @Description  Exige a chave administrativa.
+// @Tags         medidas
+// @Security     AdminKey
+// @Param        foodId path string true "ID do Alimento"
+// @Param        measureName path string true "Nome da medida"
+// @Success      204 "Medida removida"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Failure      404 {object} string "Medida não encontrada"
+// @Router       /foods/{foodId}/measures/{measureName} [delete]
+
+func (h *FoodHandler) DeleteFoodMeasure(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+	measureName := chi.URLParam(r, "measureName")
+
+	if err := h.tacoRepo.DeleteMeasure(r.Context(), foodId, measureName); err != nil {
+		respondWithMeasureError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+)
+
+// QueryFoods godoc
+// @Summary      Busca alimentos por critérios de nutrientes
+// @Description  Filtra os alimentos da TACO por faixas de nutrientes por 100 g (min_<nutriente> e max_<nutriente>, ex: min_protein_g=20&max_fat_g=5) e ordena por qualquer nutriente. Com density=true a ordenação usa o valor por 100 kcal. Nutrientes aceitos: energy_kcal, protein_g, carbohydrate_g, fat_g, fiber_g e as chaves de nutrients (sodium, iron, vitamin_c...). Alimentos sem análise do nutriente (NA) ficam fora do filtro e da ordenação; traço conta como zero.
+// @Tags         alimentos
+// @Produce      json
+// @Param        min_protein_g query number false "Exemplo de limite inferior por 100 g" example(20)
+// @Param        max_fat_g query number false "Exemplo de limite superior por 100 g" example(5)
+// @Param        sort query string false "Nutriente usado na ordenação" example(fiber_g)
+// @Param        order query string false "asc ou desc (padrão desc)"
+// @Param        density query bool false "Ordenar pelo valor por 100 kcal"
+// @Param        group query string false "Slug do grupo de alimentos" example(frutas)
+// @Param        exclude_allergens query string false "Alérgenos a excluir, separados por vírgula"
+// @Param        diet query string false "Dieta exigida (vegan ou vegetarian)"
+// @Param        limit query int false "Quantidade máxima de itens por página (1 a 100, padrão 25)"
+// @Param        cursor query string false "Cursor opaco da próxima página, obtido do header Link"
+// @Success      200 {array} model.Food "Alimentos que atendem aos critérios"
+// @Header       200 {string} Link "Link rel=\"next\" para a próxima página, quando houver"
+// @Failure      400 {object} string "Parâmetros inválidos"
+// @Failure      500 {object} string "Erro interno ao consultar alimentos"
+// @Router       /foods/query [get]
+
+func (h *FoodHandler) QueryFoods(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	startKey, ok := h.parseCursor(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := h.parseSearchFilter(w, r)
+	if !ok {
+		return
+	}
+
+	query := client.NutrientQuery{
+		Filter:            filter,
+		SortBy:            params.Get("sort"),
+		Limit:             limit,
+		ExclusiveStartKey: startKey,
+	}
+
+	switch params.Get("order") {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'order' deve ser 'asc' ou 'desc'")
+		return
+	}
+
+	if rawDensity := params.Get("density"); rawDensity != "" {
+		density, err := strconv.ParseBool(rawDensity)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Parâmetro 'density' deve ser true ou false")
+			return
+		}
+		query.Density = density
+	}
+
+	ranges := map[string]*client.NutrientRange{}
+	for name, values := range params {
+		var key string
+		var isMin bool
+		switch {
+		case strings.HasPrefix(name, "min_"):
+			key, isMin = strings.TrimPrefix(name, "min_"), true
+		case strings.HasPrefix(name, "max_"):
+			key = strings.TrimPrefix(name, "max_")
+		default:
+			continue
+		}
+
+		value, err := parseDecimal(values[0])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parâmetro '%s' deve ser numérico", name))
+			return
+		}
+
+		rg, exists := ranges[key]
+		if !exists {
+			rg = &client.NutrientRange{Key: key}
+			ranges[key] = rg
+		}
+		if isMin {
+			rg.Min = &value
+		} else {
+			rg.Max = &value
+		}
+	}
+	for _, rg := range ranges {
+		query.Ranges = append(query.Ranges, *rg)
+	}
+
+	page, err := h.nutrientIndex.Query(r.Context(), query)
+	if errors.Is(err, client.ErrInvalidNutrientQuery) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, client.ErrInvalidCursor) {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'cursor' não corresponde a esta consulta")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao consultar alimentos")
+		return
+	}
+
+	results := make([]model.Food, 0, len(page.Items))
+	for _, item := range page.Items {
+		results = append(results, client.MapTacoToFood(item))
+	}
+
+	h.setNextLink(w, r, limit, page.LastEvaluatedKey)
+
+	h.resolver.ApplyTags(r.Context(), results)
+	h.resolver.Localize(r.Context(), results)
+	RespondWithJSON(w, http.StatusOK, results)
+}
+
+// parseDecimal lê um número de query string aceitando vírgula decimal. NaN e infinitos são
+// recusados: ParseFloat os aceita ("NaN", "Inf") e eles anulariam qualquer comparação.
+func parseDecimal(raw string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.Replace(raw, ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("valor '%s' não é finito", raw)
+	}
+	return value, nil
+}
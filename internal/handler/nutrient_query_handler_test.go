@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+func TestQueryFoodsBadInput(t *testing.T) {
+	th := newTestFoodHandler(t)
+	for _, query := range []string{
+		"min_protein_g=NaN",
+		"max_fat_g=Inf",
+		"min_fiber_g=-Inf",
+		"max_fat_g=%2BInfinity",
+		"min_protein_g=muito",
+		"min_gordura_trans=1",
+		"min_protein_g=10&max_protein_g=5",
+		"order=cima",
+		"density=talvez",
+		"density=true",
+		"sort=energy_kcal&density=true",
+	} {
+		target := "/api/foods/query?" + query
+		if rec := th.do(t, http.MethodGet, target, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d; esperava 400: %s", target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestQueryFoodsFiltersAndSorts(t *testing.T) {
+	th := newTestFoodHandler(t)
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "min_protein_g=4,6", want: []string{"taco-561"}},
+		{query: "min_fiber_g=8&sort=protein_g&order=asc", want: []string{"taco-567", "taco-561"}},
+		{query: "max_energy_kcal=80&sort=fiber_g", want: []string{"taco-561", "taco-567", "taco-182"}},
+		{query: "group=cereais&sort=carbohydrate_g", want: []string{"taco-3", "taco-1"}},
+	}
+	for _, c := range cases {
+		rec := th.do(t, http.MethodGet, "/api/foods/query?"+c.query, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET ?%s = %d: %s", c.query, rec.Code, rec.Body.String())
+		}
+		if got := foodIDs(decodeJSON[[]model.Food](t, rec)); strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("GET ?%s = %v; esperava %v", c.query, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/nutrition"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type portionRequest struct {
+	MeasureName string  `json:"measure_name,omitempty" example:"colher de sopa cheia"`
+	Grams       float64 `json:"grams,omitempty" example:"0"`
+	// Quantity é opcional (padrão 1); informado, deve ser positivo.
+	Quantity *float64 `json:"quantity,omitempty" example:"2"`
+}
+
+// CalculateFoodPortion godoc
+// @Summary      Calcula nutrientes de uma porção
+// @Description  Escala os valores por 100 g do alimento para uma porção informada por medida caseira (measure_name) ou gramas (grams), multiplicada por quantity (opcional, padrão 1; zero ou negativo é recusado). As regras de arredondamento aplicadas vêm no campo rounding.
+// @Tags         alimentos
+// @Accept       json
+// @Produce      json
+// @Param        foodId path string true "ID do Alimento"
+// @Param        portion body portionRequest true "Medida ou gramas e quantidade"
+// @Success      200 {object} model.PortionResponse "Nutrientes da porção"
+// @Failure      400 {object} string "Dados inválidos"
+// @Failure      404 {object} string "Alimento ou medida não encontrados"
+// @Router       /foods/{foodId}/portion [post]
+
+func (h *FoodHandler) CalculateFoodPortion(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+
+	var req portionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+	quantity := 1.0
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	}
+	if quantity <= 0 {
+		RespondWithError(w, http.StatusBadRequest, "Campo 'quantity' deve ser positivo")
+		return
+	}
+	if (req.MeasureName == "") == (req.Grams == 0) {
+		RespondWithError(w, http.StatusBadRequest, "Informe 'measure_name' ou 'grams' (apenas um deles)")
+		return
+	}
+	if req.Grams < 0 {
+		RespondWithError(w, http.StatusBadRequest, "Campo 'grams' deve ser positivo")
+		return
+	}
+
+	ctx := r.Context()
+	food, err := h.resolver.GetFood(ctx, foodId)
+	if errors.Is(err, client.ErrFoodNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimento")
+		return
+	}
+
+	measureLabel := "grama"
+	gramsPerUnit := req.Grams
+	if req.MeasureName != "" {
+		// As medidas já vêm com o alimento; não há por que buscá-las de novo.
+		measure, ok := client.FindMeasure(client.FoodMeasureItems(food), req.MeasureName)
+		if !ok {
+			RespondWithError(w, http.StatusNotFound, "Medida caseira não encontrada para este alimento")
+			return
+		}
+		measureLabel = measure.DisplayName
+		gramsPerUnit = measure.GramEquivalent
+	}
+
+	totalGrams := gramsPerUnit * quantity
+	log.Printf("Calculando porção de %s: %.2f x %s = %.2f g", foodId, quantity, measureLabel, totalGrams)
+
+	RespondWithJSON(w, http.StatusOK, nutrition.CalculatePortion(*food, measureLabel, quantity, totalGrams))
+}
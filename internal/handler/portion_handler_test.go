@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+func TestCalculateFoodPortionBadInput(t *testing.T) {
+	th := newTestFoodHandler(t)
+	cases := map[string]string{
+		"corpo inválido":           `{"grams":`,
+		"quantity zero":            `{"grams":100,"quantity":0}`,
+		"quantity negativa":        `{"measure_name":"colher de sopa cheia","quantity":-1}`,
+		"medida e gramas":          `{"measure_name":"colher de sopa cheia","grams":100}`,
+		"nem medida nem gramas":    `{"quantity":2}`,
+		"gramas negativas":         `{"grams":-10}`,
+		"quantity zero com medida": `{"measure_name":"colher de sopa cheia","quantity":0}`,
+		"gramas em texto":          `{"grams":"cem"}`,
+		"quantity negativa fração": `{"grams":50,"quantity":-0.5}`,
+	}
+	for name, body := range cases {
+		if rec := th.do(t, http.MethodPost, "/api/foods/taco-3/portion", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: POST %s = %d; esperava 400: %s", name, body, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestCalculateFoodPortion(t *testing.T) {
+	th := newTestFoodHandler(t)
+	cases := []struct {
+		name         string
+		foodID       string
+		body         string
+		wantMeasure  string
+		wantQuantity float64
+		wantGrams    float64
+		wantKcal     float64
+	}{
+		{name: "gramas com quantity padrão", foodID: "taco-3", body: `{"grams":100}`, wantMeasure: "grama", wantQuantity: 1, wantGrams: 100, wantKcal: 128},
+		{name: "medida pela chave", foodID: "taco-3", body: `{"measure_name":"colher de sopa cheia","quantity":2}`, wantMeasure: "1 colher de sopa cheia", wantQuantity: 2, wantGrams: 50, wantKcal: 64},
+		{name: "medida pelo nome exibido, caixa e acentos", foodID: "taco-3", body: `{"measure_name":"1 Colhér de Sopa CHEIA"}`, wantMeasure: "1 colher de sopa cheia", wantQuantity: 1, wantGrams: 25, wantKcal: 32},
+		{name: "medida fracionada", foodID: "taco-182", body: `{"measure_name":"unidade média","quantity":0.5}`, wantMeasure: "1 unidade média", wantQuantity: 0.5, wantGrams: 65, wantKcal: 36},
+	}
+	for _, c := range cases {
+		rec := th.do(t, http.MethodPost, "/api/foods/"+c.foodID+"/portion", c.body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: POST = %d: %s", c.name, rec.Code, rec.Body.String())
+		}
+		got := decodeJSON[model.PortionResponse](t, rec)
+		if got.FoodID != c.foodID || got.Measure != c.wantMeasure || got.Quantity != c.wantQuantity || got.Grams != c.wantGrams || got.EnergyKcal != c.wantKcal {
+			t.Errorf("%s: porção = %+v; esperava %s x %g = %g g, %g kcal", c.name, got, c.wantMeasure, c.wantQuantity, c.wantGrams, c.wantKcal)
+		}
+	}
+}
+
+func TestCalculateFoodPortionNotFound(t *testing.T) {
+	th := newTestFoodHandler(t)
+	cases := map[string]struct{ foodID, body string }{
+		"alimento inexistente":     {"taco-9999", `{"grams":100}`},
+		"medida de outro alimento": {"taco-3", `{"measure_name":"concha média"}`},
+		"medida inexistente":       {"taco-561", `{"measure_name":"xícara"}`},
+	}
+	for name, c := range cases {
+		if rec := th.do(t, http.MethodPost, "/api/foods/"+c.foodID+"/portion", c.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: POST = %d; esperava 404: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
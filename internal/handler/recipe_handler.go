@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/tenant"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RecipeHandler struct {
+	resolver *client.FoodResolver
+}
+
+func NewRecipeHandler(resolver *client.FoodResolver) *RecipeHandler {
+	return &RecipeHandler{
+		resolver: resolver,
+	}
+}
+
+func respondWithRecipeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, client.ErrInvalidRecipe):
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, client.ErrFoodNotFound):
+		RespondWithError(w, http.StatusNotFound, "Receita não encontrada")
+	default:
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao processar receita")
+	}
+}
+
+// CreateRecipe godoc
+// @Summary      Cadastra receita
+// @Description  Cadastra uma receita da clínica. Cada ingrediente informa food_id (TACO, alimento próprio ou outra receita), measure_name ou grams, e quantity. yield_grams é o peso da preparação pronta; se omitido, usa a soma dos ingredientes.
+// @Tags         receitas
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        recipe body model.Recipe true "Receita (id é ignorado)"
+// @Success      201 {object} model.RecipeNutrition "Receita criada com nutrientes calculados"
+// @Failure      400 {object} string "Receita inválida"
+// @Failure      401 {object} string "Autenticação obrigatória"
+// @Router       /recipes [post]
+
+func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, _ := tenant.FromContext(ctx)
+
+	var recipe model.Recipe
+	if err := json.NewDecoder(r.Body).Decode(&recipe); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+	recipe.Id = ""
+
+	recipe, err := client.NormalizeRecipe(recipe)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+	snapshot, _, err := h.resolver.ComputeRecipe(ctx, recipe)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+
+	created, err := h.resolver.Recipes.CreateRecipe(ctx, tenantID, recipe, snapshot)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+
+	_, result, err := h.resolver.ComputeRecipe(ctx, *created)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusCreated, result)
+}
+
+// ListRecipes godoc
+// @Summary      Lista receitas
+// @Tags         receitas
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} model.Recipe "Receitas da clínica"
+// @Router       /recipes [get]
+
+func (h *RecipeHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	recipes, err := h.resolver.Recipes.ListRecipes(r.Context(), tenantID)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, recipes)
+}
+
+// GetRecipe godoc
+// @Summary      Busca receita com nutrientes
+// @Description  Recalcula os nutrientes por 100 g, por porção e por ingrediente a partir dos dados atuais dos ingredientes.
+// @Tags         receitas
+// @Produce      json
+// @Security     BearerAuth
+// @Param        recipeId path string true "ID da receita"
+// @Success      200 {object} model.RecipeNutrition "Receita com nutrientes"
+// @Failure      404 {object} string "Receita não encontrada"
+// @Router       /recipes/{recipeId} [get]
+
+func (h *RecipeHandler) GetRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, _ := tenant.FromContext(ctx)
+
+	recipe, err := h.resolver.Recipes.GetRecipe(ctx, tenantID, chi.URLParam(r, "recipeId"))
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+
+	_, result, err := h.resolver.ComputeRecipe(ctx, *recipe)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
+// UpdateRecipe godoc
+// @Summary      Edita receita
+// @Tags         receitas
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        recipeId path string true "ID da receita"
+// @Param        recipe body model.Recipe true "Receita"
+// @Success      200 {object} model.RecipeNutrition "Receita atualizada"
+// @Failure      400 {object} string "Receita inválida"
+// @Failure      404 {object} string "Receita não encontrada"
+// @Router       /recipes/{recipeId} [put]
+
+func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, _ := tenant.FromContext(ctx)
+
+	var recipe model.Recipe
+	if err := json.NewDecoder(r.Body).Decode(&recipe); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+	recipe.Id = chi.URLParam(r, "recipeId")
+
+	recipe, err := client.NormalizeRecipe(recipe)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+	snapshot, result, err := h.resolver.ComputeRecipe(ctx, recipe)
+	if err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+
+	if _, err := h.resolver.Recipes.UpdateRecipe(ctx, tenantID, recipe.Id, recipe, snapshot); err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
+// DeleteRecipe godoc
+// @Summary      Remove receita
+// @Tags         receitas
+// @Security     BearerAuth
+// @Param        recipeId path string true "ID da receita"
+// @Success      204 "Receita removida"
+// @Failure      404 {object} string "Receita não encontrada"
+// @Router       /recipes/{recipeId} [delete]
+
+func (h *RecipeHandler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := tenant.FromContext(r.Context())
+
+	if err := h.resolver.Recipes.DeleteRecipe(r.Context(), tenantID, chi.URLParam(r, "recipeId")); err != nil {
+		respondWithRecipeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
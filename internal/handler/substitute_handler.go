@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/model"
+	"saas-nutri/internal/nutrition"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultSubstituteLimit = 5
+	maxSubstituteLimit     = 20
+)
+
+// GetFoodSubstitutes godoc
+// @Summary      Sugere substitutos para um alimento
+// @Description  Busca na TACO os alimentos cujo perfil de macronutrientes, numa porção de mesma energia, é o mais próximo da porção original (grams gramas). A porção sugerida vem em gramas e nas medidas caseiras do substituto, da mais adequada para a menos adequada.
+// @Tags         alimentos
+// @Produce      json
+// @Param        foodId path string true "ID do Alimento"
+// @Param        grams query number false "Gramas da porção original (padrão 100)" example(100)
+// @Param        same_group query bool false "Restringe ao grupo do alimento original"
+// @Param        exclude_allergens query string false "Alérgenos a excluir, separados por vírgula (gluten, lactose, nuts, shellfish, egg, soy)" example(gluten,lactose)
+// @Param        diet query string false "Dieta exigida (vegan ou vegetarian)"
+// @Param        limit query int false "Quantidade de sugestões (1 a 20, padrão 5)"
+// @Success      200 {object} model.SubstitutesResponse "Sugestões de substituição"
+// @Failure      400 {object} string "Parâmetros inválidos ou alimento sem energia"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Failure      500 {object} string "Erro interno ao buscar substitutos"
+// @Router       /foods/{foodId}/substitutes [get]
+
+func (h *FoodHandler) GetFoodSubstitutes(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+	params := r.URL.Query()
+
+	grams := 100.0
+	if rawGrams := params.Get("grams"); rawGrams != "" {
+		parsed, err := parseDecimal(rawGrams)
+		if err != nil || parsed <= 0 {
+			RespondWithError(w, http.StatusBadRequest, "Parâmetro 'grams' deve ser um número positivo")
+			return
+		}
+		grams = parsed
+	}
+
+	limit := defaultSubstituteLimit
+	if rawLimit := params.Get("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed < 1 || parsed > maxSubstituteLimit {
+			RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parâmetro 'limit' deve ser um número entre 1 e %d", maxSubstituteLimit))
+			return
+		}
+		limit = parsed
+	}
+
+	sameGroup := false
+	if rawSameGroup := params.Get("same_group"); rawSameGroup != "" {
+		parsed, err := strconv.ParseBool(rawSameGroup)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Parâmetro 'same_group' deve ser true ou false")
+			return
+		}
+		sameGroup = parsed
+	}
+
+	filter, ok := h.parseSearchFilter(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	original, err := h.resolver.GetFood(ctx, foodId)
+	if errors.Is(err, client.ErrFoodNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimento")
+		return
+	}
+	if original.EnergyKcal <= 0 {
+		RespondWithError(w, http.StatusBadRequest, "Alimento sem valor energético; não é possível calcular porção equivalente")
+		return
+	}
+
+	foods, err := h.nutrientIndex.Foods(ctx)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar substitutos")
+		return
+	}
+
+	if sameGroup {
+		filter.FoodGroup = original.FoodGroup
+	}
+	h.resolver.ApplyTags(ctx, foods)
+	h.resolver.Localize(ctx, foods)
+
+	suggestions := nutrition.RankSubstitutes(*original, grams, filter.FilterFoods(foods), limit)
+
+	var wg sync.WaitGroup
+	for i := range suggestions {
+		wg.Add(1)
+		go func(s *model.SubstituteSuggestion) {
+			defer wg.Done()
+			measures, err := h.resolver.GetMeasures(ctx, s.Food.Id)
+			if err != nil {
+				log.Printf("Erro ao buscar medidas do substituto %s, sugerindo apenas gramas: %v", s.Food.Id, err)
+			}
+			// As porções usam os mesmos nomes exibidos no alimento, já traduzidos.
+			household := make([]model.HouseholdMeasure, 0, len(measures))
+			for _, m := range measures {
+				household = append(household, m.HouseholdMeasure())
+			}
+			s.Food.HouseholdMeasures = household
+			s.Portions = nutrition.SuggestPortions(s.Grams, household)
+		}(&suggestions[i])
+	}
+	wg.Wait()
+
+	factor := grams / 100
+	RespondWithJSON(w, http.StatusOK, model.SubstitutesResponse{
+		FoodID:        original.Id,
+		FoodName:      original.Name,
+		Grams:         nutrition.Round(grams, 1),
+		EnergyKcal:    nutrition.Round(original.EnergyKcal*factor, 0),
+		ProteinG:      nutrition.Round(original.ProteinG*factor, 1),
+		CarbohydrateG: nutrition.Round(original.CarbohydrateG*factor, 1),
+		FatG:          nutrition.Round(original.FatG*factor, 1),
+		FiberG:        nutrition.Round(original.FiberG*factor, 1),
+		Substitutes:   suggestions,
+	})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+func TestGetFoodSubstitutesBadGrams(t *testing.T) {
+	th := newTestFoodHandler(t)
+	for _, grams := range []string{"NaN", "Inf", "-Inf", "0", "-50", "cem"} {
+		target := "/api/foods/taco-561/substitutes?grams=" + grams
+		if rec := th.do(t, http.MethodGet, target, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d; esperava 400: %s", target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestGetFoodSubstitutes(t *testing.T) {
+	th := newTestFoodHandler(t)
+
+	rec := th.do(t, http.MethodGet, "/api/foods/taco-561/substitutes?grams=172&same_group=true", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET substitutos = %d: %s", rec.Code, rec.Body.String())
+	}
+	resp := decodeJSON[model.SubstitutesResponse](t, rec)
+	if len(resp.Substitutes) != 1 || resp.Substitutes[0].Food.Id != "taco-567" {
+		t.Fatalf("esperava só o feijão preto como substituto no grupo: %+v", resp.Substitutes)
+	}
+	portions := resp.Substitutes[0].Portions
+	if len(portions) != 2 || portions[0].MeasureName != "1 concha média" || portions[0].Quantity != 2 {
+		t.Errorf("porções deveriam usar o nome exibido da medida: %+v", portions)
+	}
+	if last := portions[len(portions)-1]; last.MeasureName != "Grama" {
+		t.Errorf("a última porção deveria ser em gramas: %+v", last)
+	}
+
+	if rec := th.do(t, http.MethodGet, "/api/foods/taco-9999/substitutes", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("alimento inexistente = %d; esperava 404", rec.Code)
+	}
+}
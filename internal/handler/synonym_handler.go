@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"saas-nutri/internal/client"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SynonymHandler struct {
+	synonyms *client.SynonymDictionary
+}
+
+func NewSynonymHandler(synonyms *client.SynonymDictionary) *SynonymHandler {
+	return &SynonymHandler{synonyms: synonyms}
+}
+
+type synonymRequest struct {
+	Synonyms []string `json:"synonyms" example:"aipim,macaxeira"`
+}
+
+// ListSynonyms godoc
+// @Summary      Lista o dicionário de sinônimos
+// @Description  Retorna os grupos embutidos (source builtin) seguidos dos cadastrados pela curadoria (source admin). Termos já normalizados, sem acentos.
+// @Tags         curadoria
+// @Produce      json
+// @Security     AdminKey
+// @Success      200 {array} client.SynonymEntry "Grupos de sinônimos"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Router       /admin/synonyms [get]
+
+func (h *SynonymHandler) ListSynonyms(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.synonyms.List(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao listar sinônimos")
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, entries)
+}
+
+// PutSynonym godoc
+// @Summary      Cadastra sinônimos de um termo
+// @Description  Declara que o termo e os sinônimos nomeiam o mesmo alimento. A relação vale nos dois sentidos e grupos com termos em comum são unidos. Substitui o cadastro anterior do termo.
+// @Tags         curadoria
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Param        term path string true "Termo (ex: nome usado na TACO)" example(mandioca)
+// @Param        synonyms body synonymRequest true "Sinônimos do termo"
+// @Success      200 {object} client.SynonymEntry "Sinônimos gravados"
+// @Failure      400 {object} string "Sinônimos inválidos"
+// @Router       /admin/synonyms/{term} [put]
+
+func (h *SynonymHandler) PutSynonym(w http.ResponseWriter, r *http.Request) {
+	term := chi.URLParam(r, "term")
+
+	var req synonymRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	saved, err := h.synonyms.Put(r.Context(), client.SynonymEntry{Term: term, Synonyms: req.Synonyms})
+	if errors.Is(err, client.ErrInvalidSynonym) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao gravar sinônimos")
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, saved)
+}
+
+// DeleteSynonym godoc
+// @Summary      Remove os sinônimos cadastrados de um termo
+// @Description  Apenas grupos cadastrados pela curadoria podem ser removidos; os embutidos continuam valendo.
+// @Tags         curadoria
+// @Security     AdminKey
+// @Param        term path string true "Termo"
+// @Success      204 "Sinônimos removidos"
+// @Failure      404 {object} string "Termo sem sinônimos cadastrados"
+// @Router       /admin/synonyms/{term} [delete]
+
+func (h *SynonymHandler) DeleteSynonym(w http.ResponseWriter, r *http.Request) {
+	term := chi.URLParam(r, "term")
+
+	err := h.synonyms.Delete(r.Context(), term)
+	if errors.Is(err, client.ErrSynonymNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Termo sem sinônimos cadastrados")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao remover sinônimos")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
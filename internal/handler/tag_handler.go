@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"saas-nutri/internal/client"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TagHandler struct {
+	tagger   *client.FoodTagger
+	resolver *client.FoodResolver
+}
+
+func NewTagHandler(tagger *client.FoodTagger, resolver *client.FoodResolver) *TagHandler {
+	return &TagHandler{tagger: tagger, resolver: resolver}
+}
+
+type tagOverrideRequest struct {
+	Allergens []string `json:"allergens" example:"gluten,lactose"`
+	Diets     []string `json:"diets" example:"vegetarian"`
+	Note      string   `json:"note,omitempty" example:"Contém traços de leite segundo o fabricante"`
+}
+
+// ListTagOverrides godoc
+// @Summary      Lista as correções manuais de tags
+// @Tags         curadoria
+// @Produce      json
+// @Security     AdminKey
+// @Success      200 {array} client.TagOverride "Correções cadastradas"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Router       /admin/tag-overrides [get]
+
+func (h *TagHandler) ListTagOverrides(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.tagger.ListOverrides(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao listar correções de tags")
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, overrides)
+}
+
+// PutTagOverride godoc
+// @Summary      Corrige as tags de um alimento
+// @Description  Substitui por completo os alérgenos e dietas calculados para o alimento. Dieta vegan implica vegetarian.
+// @Tags         curadoria
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Param        foodId path string true "ID do Alimento"
+// @Param        tags body tagOverrideRequest true "Alérgenos e dietas corretos"
+// @Success      200 {object} client.TagOverride "Correção gravada"
+// @Failure      400 {object} string "Tags inválidas"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Router       /admin/tag-overrides/{foodId} [put]
+
+func (h *TagHandler) PutTagOverride(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+
+	var req tagOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	ctx := r.Context()
+	// Alimentos próprios e receitas pertencem a um tenant e não podem ser conferidos aqui.
+	if !client.IsCustomFoodID(foodId) && !client.IsRecipeID(foodId) {
+		_, err := h.resolver.GetFood(ctx, foodId)
+		if errors.Is(err, client.ErrFoodNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+			return
+		}
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimento")
+			return
+		}
+	}
+
+	saved, err := h.tagger.PutOverride(ctx, client.TagOverride{
+		FoodID:    foodId,
+		Allergens: req.Allergens,
+		Diets:     req.Diets,
+		Note:      req.Note,
+	})
+	if errors.Is(err, client.ErrInvalidTags) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao gravar correção de tags")
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, saved)
+}
+
+// DeleteTagOverride godoc
+// @Summary      Remove a correção de tags de um alimento
+// @Description  O alimento volta a usar as tags calculadas pelas regras ou pela fonte de origem.
+// @Tags         curadoria
+// @Security     AdminKey
+// @Param        foodId path string true "ID do Alimento"
+// @Success      204 "Correção removida"
+// @Failure      404 {object} string "Correção não encontrada"
+// @Router       /admin/tag-overrides/{foodId} [delete]
+
+func (h *TagHandler) DeleteTagOverride(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+
+	err := h.tagger.DeleteOverride(r.Context(), foodId)
+	if errors.Is(err, client.ErrTagOverrideNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Correção de tags não encontrada")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao remover correção de tags")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"saas-nutri/internal/client"
+	"saas-nutri/internal/locale"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TranslationHandler struct {
+	translator *client.Translator
+	resolver   *client.FoodResolver
+}
+
+func NewTranslationHandler(translator *client.Translator, resolver *client.FoodResolver) *TranslationHandler {
+	return &TranslationHandler{translator: translator, resolver: resolver}
+}
+
+type translationRequest struct {
+	Name     string            `json:"name" example:"Manzana Fuji con cáscara, cruda"`
+	Measures map[string]string `json:"measures,omitempty"`
+}
+
+// ListTranslations godoc
+// @Summary      Lista as traduções de alimentos
+// @Description  Nomes de alimentos e de medidas caseiras em outros idiomas. As chaves de measures são os nomes de exibição em português, normalizados.
+// @Tags         curadoria
+// @Produce      json
+// @Security     AdminKey
+// @Param        locale query string false "Filtra por idioma (es ou en)"
+// @Success      200 {array} client.FoodTranslation "Traduções cadastradas"
+// @Failure      400 {object} string "Idioma inválido"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Router       /admin/translations [get]
+
+func (h *TranslationHandler) ListTranslations(w http.ResponseWriter, r *http.Request) {
+	filterLocale := ""
+	if rawLocale := r.URL.Query().Get("locale"); rawLocale != "" {
+		l, ok := locale.Normalize(rawLocale)
+		if !ok {
+			RespondWithError(w, http.StatusBadRequest, "Parâmetro 'locale' inválido")
+			return
+		}
+		filterLocale = l
+	}
+
+	translations, err := h.translator.List(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao listar traduções")
+		return
+	}
+	filtered := []client.FoodTranslation{}
+	for _, t := range translations {
+		if filterLocale == "" || t.Locale == filterLocale {
+			filtered = append(filtered, t)
+		}
+	}
+	RespondWithJSON(w, http.StatusOK, filtered)
+}
+
+// PutTranslation godoc
+// @Summary      Cadastra a tradução de um alimento
+// @Description  Define o nome do alimento e, opcionalmente, das medidas caseiras no idioma. measures mapeia o nome de exibição em português (ex: "1 unidade média") para o traduzido. Substitui a tradução anterior do idioma; a busca nesse idioma passa a usar o novo nome.
+// @Tags         curadoria
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Param        foodId path string true "ID do Alimento"
+// @Param        locale path string true "Idioma (es ou en)"
+// @Param        translation body translationRequest true "Nome e medidas traduzidos"
+// @Success      200 {object} client.FoodTranslation "Tradução gravada"
+// @Failure      400 {object} string "Tradução inválida"
+// @Failure      404 {object} string "Alimento não encontrado"
+// @Router       /admin/translations/{foodId}/{locale} [put]
+
+func (h *TranslationHandler) PutTranslation(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+
+	var req translationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Corpo da requisição inválido")
+		return
+	}
+
+	ctx := r.Context()
+	_, err := h.resolver.GetFood(ctx, foodId)
+	if errors.Is(err, client.ErrFoodNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Alimento não encontrado")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao buscar alimento")
+		return
+	}
+
+	saved, err := h.translator.Put(ctx, client.FoodTranslation{
+		FoodID:   foodId,
+		Locale:   chi.URLParam(r, "locale"),
+		Name:     req.Name,
+		Measures: req.Measures,
+	})
+	if errors.Is(err, client.ErrInvalidTranslation) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao gravar tradução")
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, saved)
+}
+
+// DeleteTranslation godoc
+// @Summary      Remove a tradução de um alimento
+// @Description  O alimento volta a aparecer com o nome em português nesse idioma.
+// @Tags         curadoria
+// @Security     AdminKey
+// @Param        foodId path string true "ID do Alimento"
+// @Param        locale path string true "Idioma (es ou en)"
+// @Success      204 "Tradução removida"
+// @Failure      404 {object} string "Tradução não encontrada"
+// @Router       /admin/translations/{foodId}/{locale} [delete]
+
+func (h *TranslationHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	foodId := chi.URLParam(r, "foodId")
+	l, ok := locale.Normalize(chi.URLParam(r, "locale"))
+	if !ok {
+		RespondWithError(w, http.StatusNotFound, "Tradução não encontrada")
+		return
+	}
+
+	err := h.translator.Delete(r.Context(), foodId, l)
+	if errors.Is(err, client.ErrTranslationNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Tradução não encontrada")
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao remover tradução")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"saas-nutri/internal/client"
+)
+
+type ValidationHandler struct {
+	foods client.FoodRepository
+}
+
+func NewValidationHandler(foods client.FoodRepository) *ValidationHandler {
+	return &ValidationHandler{foods: foods}
+}
+
+// GetValidationReport godoc
+// @Summary      Relatório de qualidade dos alimentos
+// @Description  Valida todos os alimentos TACO: energia declarada x Atwater, valores impossíveis, macronutrientes acima de 100 g, fibra acima do carboidrato e medidas caseiras ausentes ou inválidas. Lista só os alimentos com problema; severity=error restringe aos que têm erro.
+// @Tags         curadoria
+// @Produce      json
+// @Security     AdminKey
+// @Param        severity query string false "Filtra por severidade: error"
+// @Success      200 {object} client.ValidationReport "Relatório de validação"
+// @Failure      400 {object} string "Severidade inválida"
+// @Failure      401 {object} string "Chave administrativa inválida"
+// @Failure      500 {object} string "Erro interno ao validar alimentos"
+// @Router       /admin/validation [get]
+
+func (h *ValidationHandler) GetValidationReport(w http.ResponseWriter, r *http.Request) {
+	severity := r.URL.Query().Get("severity")
+	if severity != "" && severity != client.ValidationSeverityError {
+		RespondWithError(w, http.StatusBadRequest, "Parâmetro 'severity' deve ser 'error'")
+		return
+	}
+
+	report, err := client.ValidateRepository(r.Context(), h.foods)
+	if err != nil {
+		log.Printf("Erro ao gerar relatório de validação: %v", err)
+		RespondWithError(w, http.StatusInternalServerError, "Erro interno ao validar alimentos")
+		return
+	}
+
+	if severity == client.ValidationSeverityError {
+		filtered := []client.FoodValidationResult{}
+		for _, result := range report.Foods {
+			if client.HasValidationErrors(result.Issues) {
+				filtered = append(filtered, result)
+			}
+		}
+		report.Foods = filtered
+	}
+
+	RespondWithJSON(w, http.StatusOK, report)
+}
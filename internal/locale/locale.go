@@ -0,0 +1,81 @@
+// Package locale identifica o idioma pedido na requisição e o carrega no contexto. A base TACO
+// é em português, que é também o idioma usado quando falta tradução.
+package locale
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Portuguese = "pt"
+	Spanish    = "es"
+	English    = "en"
+
+	Default = Portuguese
+)
+
+var Supported = []string{Portuguese, Spanish, English}
+
+// Normalize reduz uma tag de idioma (pt-BR, es_AR, EN) ao idioma suportado correspondente.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, l := range Supported {
+		if tag == l {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// FromAcceptLanguage escolhe o idioma suportado de maior peso (q) no header Accept-Language,
+// respeitando a ordem do header em caso de empate. Sem idioma suportado, devolve Default.
+func FromAcceptLanguage(header string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		l, ok := Normalize(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if raw, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: l, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+type contextKey struct{}
+
+func WithLocale(ctx context.Context, l string) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext devolve o idioma da requisição, ou Default quando não foi identificado.
+func FromContext(ctx context.Context) string {
+	if l, ok := ctx.Value(contextKey{}).(string); ok && l != "" {
+		return l
+	}
+	return Default
+}
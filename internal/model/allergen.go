@@ -0,0 +1,33 @@
+package model
+
+const (
+	AllergenGluten    = "gluten"
+	AllergenLactose   = "lactose"
+	AllergenNuts      = "nuts"
+	AllergenShellfish = "shellfish"
+	AllergenEgg       = "egg"
+	AllergenSoy       = "soy"
+)
+
+type AllergenDefinition struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+var AllergenDefinitions = []AllergenDefinition{
+	{Key: AllergenGluten, Name: "Glúten"},
+	{Key: AllergenLactose, Name: "Lactose"},
+	{Key: AllergenNuts, Name: "Castanhas, nozes e amendoim"},
+	{Key: AllergenShellfish, Name: "Crustáceos e moluscos"},
+	{Key: AllergenEgg, Name: "Ovo"},
+	{Key: AllergenSoy, Name: "Soja"},
+}
+
+func IsAllergen(key string) bool {
+	for _, d := range AllergenDefinitions {
+		if d.Key == key {
+			return true
+		}
+	}
+	return false
+}
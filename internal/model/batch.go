@@ -0,0 +1,14 @@
+package model
+
+type BatchFoodError struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// BatchFoodsResponse traz os alimentos encontrados na ordem pedida, os ids inexistentes e os
+// ids cuja origem falhou (ex: USDA fora do ar).
+type BatchFoodsResponse struct {
+	Foods   []Food           `json:"foods"`
+	Missing []string         `json:"missing"`
+	Errors  []BatchFoodError `json:"errors,omitempty"`
+}
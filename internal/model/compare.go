@@ -0,0 +1,52 @@
+package model
+
+// Bases de normalização da comparação.
+const (
+	CompareBasis100g    = "100g"
+	CompareBasisKcal    = "kcal"
+	CompareBasisMeasure = "measure"
+)
+
+// Sentido em que um nutriente é considerado melhor. Nutrientes neutros (energia,
+// carboidrato, lipídios) dependem do objetivo do paciente e não recebem best/worst.
+const (
+	NutrientBetterHigher  = "higher"
+	NutrientBetterLower   = "lower"
+	NutrientBetterNeutral = "neutral"
+)
+
+// ComparedFood descreve a porção de cada alimento usada na comparação.
+type ComparedFood struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Source     string  `json:"source"`
+	BasisGrams float64 `json:"basis_grams"`
+	BasisLabel string  `json:"basis_label"`
+}
+
+// ComparedValue é o valor de um nutriente para um alimento, já na base escolhida.
+// DiffPercent é a diferença em relação ao alimento de referência (o primeiro id) e fica
+// nulo quando um dos valores falta ou a referência é zero.
+type ComparedValue struct {
+	FoodID      string   `json:"food_id"`
+	Value       *float64 `json:"value"`
+	Status      string   `json:"status"`
+	DiffPercent *float64 `json:"diff_percent"`
+	Best        bool     `json:"best,omitempty"`
+	Worst       bool     `json:"worst,omitempty"`
+}
+
+type NutrientComparison struct {
+	Key    string          `json:"key"`
+	Name   string          `json:"name"`
+	Unit   string          `json:"unit"`
+	Better string          `json:"better"`
+	Values []ComparedValue `json:"values"`
+}
+
+type FoodComparison struct {
+	Basis       string               `json:"basis"`
+	ReferenceID string               `json:"reference_id"`
+	Foods       []ComparedFood       `json:"foods"`
+	Nutrients   []NutrientComparison `json:"nutrients"`
+}
@@ -4,15 +4,32 @@ type Food struct {
 	Id            string             `json:"id"`
 	Name          string             `json:"name"`
 	Source        string  `json:"source"`
+	FoodGroup     string             `json:"food_group,omitempty"`
 	EnergyKcal    float64            `json:"energy_kcal"`
 	ProteinG      float64            `json:"protein_g"`
 	CarbohydrateG float64            `json:"carbohydrate_g"`
 	FatG          float64            `json:"fat_g"`
 	FiberG        float64            `json:"fiber_g"`
+	Nutrients     map[string]NutrientValue `json:"nutrients,omitempty"`
+	Tags          *FoodTags          `json:"tags,omitempty"`
+	MatchedSynonym string            `json:"matched_synonym,omitempty"`
+	// Locale é o idioma de Name quando ele foi traduzido; vazio, o nome está no idioma da fonte.
+	Locale         string            `json:"locale,omitempty"`
 	HouseholdMeasures []HouseholdMeasure `json:"household_measures"`
 }
 
 type HouseholdMeasure struct {
 	Name   string  `json:"name"`  
 	Grams  float64 `json:"grams"` 
+	// IsDefault marca a medida padrão de 1 g. Name é só o rótulo de exibição e muda com o
+	// idioma; para reconhecer a medida padrão, use este campo.
+	IsDefault bool `json:"is_default,omitempty"`
+	// Key é o nome da medida na base (measure_name da TACO, em português), quando difere de
+	// Name. Não vai no JSON; serve para achar a medida pelo nome pedido no cálculo de porções.
+	Key string `json:"-"`
+}
+
+// GramMeasure é a medida padrão de 1 g que todo alimento tem.
+func GramMeasure() HouseholdMeasure {
+	return HouseholdMeasure{Name: "Grama", Grams: 1.0, IsDefault: true, Key: "grama"}
 }
\ No newline at end of file
@@ -0,0 +1,34 @@
+package model
+
+// FoodGroup é um grupo de alimentos da TACO. Slug é o valor gravado em food_group.
+type FoodGroup struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+var FoodGroups = []FoodGroup{
+	{Slug: "cereais", Name: "Cereais e derivados"},
+	{Slug: "verduras", Name: "Verduras, hortaliças e derivados"},
+	{Slug: "frutas", Name: "Frutas e derivados"},
+	{Slug: "gorduras", Name: "Gorduras e óleos"},
+	{Slug: "pescados", Name: "Pescados e frutos do mar"},
+	{Slug: "carnes", Name: "Carnes e derivados"},
+	{Slug: "leites", Name: "Leite e derivados"},
+	{Slug: "bebidas", Name: "Bebidas (alcoólicas e não alcoólicas)"},
+	{Slug: "ovos", Name: "Ovos e derivados"},
+	{Slug: "acucarados", Name: "Produtos açucarados"},
+	{Slug: "miscelaneas", Name: "Miscelâneas"},
+	{Slug: "industrializados", Name: "Outros alimentos industrializados"},
+	{Slug: "preparados", Name: "Alimentos preparados"},
+	{Slug: "leguminosas", Name: "Leguminosas e derivados"},
+	{Slug: "nozes", Name: "Nozes e sementes"},
+}
+
+func IsFoodGroup(slug string) bool {
+	for _, g := range FoodGroups {
+		if g.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package model
+
+// Status de um nutriente. A TACO diferencia "Tr" (traço, presente abaixo do limite de
+// quantificação) e "NA" (não analisado) de um valor medido igual a zero.
+const (
+	NutrientStatusMeasured    = "measured"
+	NutrientStatusTrace       = "trace"
+	NutrientStatusNotAnalyzed = "not_analyzed"
+)
+
+const (
+	NutrientSodium             = "sodium"
+	NutrientPotassium          = "potassium"
+	NutrientCalcium            = "calcium"
+	NutrientIron               = "iron"
+	NutrientZinc               = "zinc"
+	NutrientMagnesium          = "magnesium"
+	NutrientPhosphorus         = "phosphorus"
+	NutrientVitaminA           = "vitamin_a"
+	NutrientVitaminC           = "vitamin_c"
+	NutrientVitaminB1          = "vitamin_b1"
+	NutrientVitaminB2          = "vitamin_b2"
+	NutrientVitaminB6          = "vitamin_b6"
+	NutrientVitaminB3          = "vitamin_b3"
+	NutrientCholesterol        = "cholesterol"
+	NutrientSaturatedFat       = "saturated_fat"
+	NutrientMonounsaturatedFat = "monounsaturated_fat"
+	NutrientPolyunsaturatedFat = "polyunsaturated_fat"
+)
+
+// NutrientValue é o valor de um nutriente por 100 g. Value é nulo quando o status
+// não é "measured".
+type NutrientValue struct {
+	Value  *float64 `json:"value"`
+	Unit   string   `json:"unit"`
+	Status string   `json:"status"`
+}
+
+type NutrientDefinition struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+}
+
+var NutrientDefinitions = []NutrientDefinition{
+	{Key: NutrientSodium, Name: "Sódio", Unit: "mg"},
+	{Key: NutrientPotassium, Name: "Potássio", Unit: "mg"},
+	{Key: NutrientCalcium, Name: "Cálcio", Unit: "mg"},
+	{Key: NutrientIron, Name: "Ferro", Unit: "mg"},
+	{Key: NutrientZinc, Name: "Zinco", Unit: "mg"},
+	{Key: NutrientMagnesium, Name: "Magnésio", Unit: "mg"},
+	{Key: NutrientPhosphorus, Name: "Fósforo", Unit: "mg"},
+	{Key: NutrientVitaminA, Name: "Vitamina A (RAE)", Unit: "mcg"},
+	{Key: NutrientVitaminC, Name: "Vitamina C", Unit: "mg"},
+	{Key: NutrientVitaminB1, Name: "Tiamina (B1)", Unit: "mg"},
+	{Key: NutrientVitaminB2, Name: "Riboflavina (B2)", Unit: "mg"},
+	{Key: NutrientVitaminB6, Name: "Piridoxina (B6)", Unit: "mg"},
+	{Key: NutrientVitaminB3, Name: "Niacina (B3)", Unit: "mg"},
+	{Key: NutrientCholesterol, Name: "Colesterol", Unit: "mg"},
+	{Key: NutrientSaturatedFat, Name: "Ácidos graxos saturados", Unit: "g"},
+	{Key: NutrientMonounsaturatedFat, Name: "Ácidos graxos monoinsaturados", Unit: "g"},
+	{Key: NutrientPolyunsaturatedFat, Name: "Ácidos graxos poli-insaturados", Unit: "g"},
+}
+
+func NutrientUnit(key string) string {
+	for _, d := range NutrientDefinitions {
+		if d.Key == key {
+			return d.Unit
+		}
+	}
+	return ""
+}
+
+func MeasuredNutrient(key string, value float64) NutrientValue {
+	return NutrientValue{Value: &value, Unit: NutrientUnit(key), Status: NutrientStatusMeasured}
+}
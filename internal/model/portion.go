@@ -0,0 +1,27 @@
+package model
+
+type RoundingRule struct {
+	Fields   []string `json:"fields"`
+	Decimals int      `json:"decimals"`
+}
+
+type RoundingPolicy struct {
+	Method string         `json:"method"`
+	Stage  string         `json:"stage"`
+	Rules  []RoundingRule `json:"rules"`
+}
+
+type PortionResponse struct {
+	FoodID        string                   `json:"food_id"`
+	FoodName      string                   `json:"food_name"`
+	Measure       string                   `json:"measure"`
+	Quantity      float64                  `json:"quantity"`
+	Grams         float64                  `json:"grams"`
+	EnergyKcal    float64                  `json:"energy_kcal"`
+	ProteinG      float64                  `json:"protein_g"`
+	CarbohydrateG float64                  `json:"carbohydrate_g"`
+	FatG          float64                  `json:"fat_g"`
+	FiberG        float64                  `json:"fiber_g"`
+	Nutrients     map[string]NutrientValue `json:"nutrients,omitempty"`
+	Rounding      RoundingPolicy           `json:"rounding"`
+}
@@ -0,0 +1,28 @@
+package model
+
+type RecipeIngredient struct {
+	FoodID      string  `json:"food_id"`
+	MeasureName string  `json:"measure_name,omitempty"`
+	Grams       float64 `json:"grams,omitempty"`
+	Quantity    float64 `json:"quantity"`
+}
+
+// Recipe é um alimento composto. YieldGrams é o peso final da preparação pronta, usado para
+// aplicar o fator de cocção (perda ou ganho de água) sobre a soma crua dos ingredientes.
+type Recipe struct {
+	Id          string             `json:"id"`
+	Name        string             `json:"name"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	YieldGrams  float64            `json:"yield_grams"`
+	Servings    float64            `json:"servings"`
+}
+
+type RecipeNutrition struct {
+	Recipe
+	RawWeightG    float64           `json:"raw_weight_g"`
+	CookingFactor float64           `json:"cooking_factor"`
+	ServingG      float64           `json:"serving_g"`
+	Per100g       PortionResponse   `json:"per_100g"`
+	PerServing    PortionResponse   `json:"per_serving"`
+	Breakdown     []PortionResponse `json:"ingredients_breakdown"`
+}
@@ -0,0 +1,15 @@
+package model
+
+type SourceStatus struct {
+	Source     string `json:"source"`
+	Status     string `json:"status"`
+	Count      int    `json:"count"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type FederatedSearchResponse struct {
+	Results []Food         `json:"results"`
+	Sources []SourceStatus `json:"sources"`
+	Partial bool           `json:"partial"`
+}
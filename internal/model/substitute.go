@@ -0,0 +1,32 @@
+package model
+
+// SubstitutePortion é a porção sugerida expressa em uma medida caseira do substituto.
+type SubstitutePortion struct {
+	MeasureName string  `json:"measure_name"`
+	Quantity    float64 `json:"quantity"`
+	Grams       float64 `json:"grams"`
+}
+
+type SubstituteSuggestion struct {
+	Food          Food                `json:"food"`
+	Grams         float64             `json:"grams"`
+	EnergyKcal    float64             `json:"energy_kcal"`
+	ProteinG      float64             `json:"protein_g"`
+	CarbohydrateG float64             `json:"carbohydrate_g"`
+	FatG          float64             `json:"fat_g"`
+	FiberG        float64             `json:"fiber_g"`
+	Distance      float64             `json:"distance"`
+	Portions      []SubstitutePortion `json:"portions"`
+}
+
+type SubstitutesResponse struct {
+	FoodID        string                 `json:"food_id"`
+	FoodName      string                 `json:"food_name"`
+	Grams         float64                `json:"grams"`
+	EnergyKcal    float64                `json:"energy_kcal"`
+	ProteinG      float64                `json:"protein_g"`
+	CarbohydrateG float64                `json:"carbohydrate_g"`
+	FatG          float64                `json:"fat_g"`
+	FiberG        float64                `json:"fiber_g"`
+	Substitutes   []SubstituteSuggestion `json:"substitutes"`
+}
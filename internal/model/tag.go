@@ -0,0 +1,45 @@
+package model
+
+const (
+	DietVegan      = "vegan"
+	DietVegetarian = "vegetarian"
+)
+
+// Origem das tags de um alimento.
+const (
+	TagSourceRules         = "rules"
+	TagSourceOpenFoodFacts = "openfoodfacts"
+	TagSourceRecipe        = "recipe"
+	TagSourceOverride      = "override"
+)
+
+// FoodTags reúne alérgenos e dietas compatíveis de um alimento. Source indica se as tags
+// vieram das regras por nome, do Open Food Facts, dos ingredientes de uma receita ou de
+// uma correção manual.
+type FoodTags struct {
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+	Source    string   `json:"source"`
+}
+
+func (t FoodTags) HasAllergen(allergen string) bool {
+	for _, a := range t.Allergens {
+		if a == allergen {
+			return true
+		}
+	}
+	return false
+}
+
+func (t FoodTags) HasDiet(diet string) bool {
+	for _, d := range t.Diets {
+		if d == diet {
+			return true
+		}
+	}
+	return false
+}
+
+func IsDiet(diet string) bool {
+	return diet == DietVegan || diet == DietVegetarian
+}
@@ -0,0 +1,195 @@
+package nutrition
+
+import "saas-nutri/internal/model"
+
+// nutrientBetter diz em que sentido cada nutriente é preferível numa comparação.
+// Nutrientes ausentes do mapa são neutros.
+var nutrientBetter = map[string]string{
+	"protein_g":                      model.NutrientBetterHigher,
+	"fiber_g":                        model.NutrientBetterHigher,
+	model.NutrientSodium:             model.NutrientBetterLower,
+	model.NutrientCholesterol:        model.NutrientBetterLower,
+	model.NutrientSaturatedFat:       model.NutrientBetterLower,
+	model.NutrientPotassium:          model.NutrientBetterHigher,
+	model.NutrientCalcium:            model.NutrientBetterHigher,
+	model.NutrientIron:               model.NutrientBetterHigher,
+	model.NutrientZinc:               model.NutrientBetterHigher,
+	model.NutrientMagnesium:          model.NutrientBetterHigher,
+	model.NutrientPhosphorus:         model.NutrientBetterHigher,
+	model.NutrientVitaminA:           model.NutrientBetterHigher,
+	model.NutrientVitaminC:           model.NutrientBetterHigher,
+	model.NutrientVitaminB1:          model.NutrientBetterHigher,
+	model.NutrientVitaminB2:          model.NutrientBetterHigher,
+	model.NutrientVitaminB6:          model.NutrientBetterHigher,
+	model.NutrientVitaminB3:          model.NutrientBetterHigher,
+	model.NutrientMonounsaturatedFat: model.NutrientBetterHigher,
+	model.NutrientPolyunsaturatedFat: model.NutrientBetterHigher,
+}
+
+var comparedMacros = []model.NutrientDefinition{
+	{Key: "energy_kcal", Name: "Energia", Unit: "kcal"},
+	{Key: "protein_g", Name: "Proteína", Unit: "g"},
+	{Key: "carbohydrate_g", Name: "Carboidrato", Unit: "g"},
+	{Key: "fat_g", Name: "Lipídios", Unit: "g"},
+	{Key: "fiber_g", Name: "Fibra alimentar", Unit: "g"},
+}
+
+// CompareBasisGrams devolve a quantidade de gramas de food que corresponde à base e o rótulo
+// dessa porção. Na base kcal, é a porção de 100 kcal; na base measure, uma unidade da medida
+// padrão (IsDefault) do alimento, e não a que vier primeiro. Quando a padrão é a própria
+// grama, ou falta, a base volta para 100 g. ok é falso quando a base não se aplica ao
+// alimento (kcal para alimento sem energia).
+func CompareBasisGrams(food model.Food, basis string) (grams float64, label string, ok bool) {
+	switch basis {
+	case model.CompareBasisKcal:
+		if food.EnergyKcal <= 0 {
+			return 0, "", false
+		}
+		return 100 / food.EnergyKcal * 100, "100 kcal", true
+	case model.CompareBasisMeasure:
+		for _, m := range food.HouseholdMeasures {
+			if m.IsDefault && m.Grams > 0 && m.Grams != model.GramMeasure().Grams {
+				return m.Grams, m.Name, true
+			}
+		}
+		return 100, "100 g (sem medida caseira padrão)", true
+	default:
+		return 100, "100 g", true
+	}
+}
+
+// macroValue lê um macronutriente por 100 g.
+func macroValue(food model.Food, key string) float64 {
+	switch key {
+	case "energy_kcal":
+		return food.EnergyKcal
+	case "protein_g":
+		return food.ProteinG
+	case "carbohydrate_g":
+		return food.CarbohydrateG
+	case "fat_g":
+		return food.FatG
+	}
+	return food.FiberG
+}
+
+// CompareFoods alinha os nutrientes dos alimentos na base escolhida. O primeiro alimento é a
+// referência das diferenças percentuais. Best e worst só são marcados em nutrientes com sentido
+// definido, quando há ao menos dois valores diferentes; traço conta como zero e nutriente não
+// analisado fica de fora. Os valores são arredondados só na saída.
+func CompareFoods(foods []model.Food, basis string) model.FoodComparison {
+	comparison := model.FoodComparison{Basis: basis}
+	if len(foods) == 0 {
+		return comparison
+	}
+	comparison.ReferenceID = foods[0].Id
+
+	factors := make([]float64, len(foods))
+	for i, food := range foods {
+		grams, label, _ := CompareBasisGrams(food, basis)
+		factors[i] = grams / 100
+		comparison.Foods = append(comparison.Foods, model.ComparedFood{
+			ID:         food.Id,
+			Name:       food.Name,
+			Source:     food.Source,
+			BasisGrams: Round(grams, 1),
+			BasisLabel: label,
+		})
+	}
+
+	for _, def := range comparedMacros {
+		values := make([]model.NutrientValue, len(foods))
+		for i, food := range foods {
+			v := macroValue(food, def.Key) * factors[i]
+			values[i] = model.NutrientValue{Value: &v, Unit: def.Unit, Status: model.NutrientStatusMeasured}
+		}
+		comparison.Nutrients = append(comparison.Nutrients, compareNutrient(def, foods, values))
+	}
+
+	for _, def := range model.NutrientDefinitions {
+		present := false
+		values := make([]model.NutrientValue, len(foods))
+		for i, food := range foods {
+			n, ok := food.Nutrients[def.Key]
+			if !ok {
+				values[i] = model.NutrientValue{Unit: def.Unit, Status: model.NutrientStatusNotAnalyzed}
+				continue
+			}
+			present = true
+			if n.Value != nil {
+				v := *n.Value * factors[i]
+				n.Value = &v
+			}
+			values[i] = n
+		}
+		if present {
+			comparison.Nutrients = append(comparison.Nutrients, compareNutrient(def, foods, values))
+		}
+	}
+	return comparison
+}
+
+// comparableValue devolve o valor usado na comparação: traço conta como zero e nutriente não
+// analisado não tem valor.
+func comparableValue(n model.NutrientValue) (float64, bool) {
+	switch {
+	case n.Value != nil:
+		return *n.Value, true
+	case n.Status == model.NutrientStatusTrace:
+		return 0, true
+	}
+	return 0, false
+}
+
+func compareNutrient(def model.NutrientDefinition, foods []model.Food, values []model.NutrientValue) model.NutrientComparison {
+	better, ok := nutrientBetter[def.Key]
+	if !ok {
+		better = model.NutrientBetterNeutral
+	}
+	row := model.NutrientComparison{Key: def.Key, Name: def.Name, Unit: def.Unit, Better: better}
+
+	decimals := unitDecimals(def.Unit)
+	if def.Unit == "kcal" {
+		decimals = 0
+	}
+
+	reference, hasReference := comparableValue(values[0])
+	best, worst := -1, -1
+	var bestValue, worstValue float64
+	for i, n := range values {
+		cell := model.ComparedValue{FoodID: foods[i].Id, Status: n.Status}
+		v, ok := comparableValue(n)
+		if n.Value != nil {
+			rounded := Round(v, decimals)
+			cell.Value = &rounded
+		}
+		if ok && hasReference && reference != 0 {
+			diff := Round((v-reference)/reference*100, 1)
+			cell.DiffPercent = &diff
+		}
+		row.Values = append(row.Values, cell)
+
+		if !ok || better == model.NutrientBetterNeutral {
+			continue
+		}
+		if best < 0 || isBetter(better, v, bestValue) {
+			best, bestValue = i, v
+		}
+		if worst < 0 || isBetter(better, worstValue, v) {
+			worst, worstValue = i, v
+		}
+	}
+
+	if best >= 0 && bestValue != worstValue {
+		row.Values[best].Best = true
+		row.Values[worst].Worst = true
+	}
+	return row
+}
+
+func isBetter(better string, a, b float64) bool {
+	if better == model.NutrientBetterLower {
+		return a < b
+	}
+	return a > b
+}
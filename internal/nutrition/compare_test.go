@@ -0,0 +1,155 @@
+package nutrition
+
+import (
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+// row devolve a linha do nutriente key na comparação.
+func row(t *testing.T, comparison model.FoodComparison, key string) model.NutrientComparison {
+	t.Helper()
+	for _, r := range comparison.Nutrients {
+		if r.Key == key {
+			return r
+		}
+	}
+	t.Fatalf("nutriente %s ausente da comparação", key)
+	return model.NutrientComparison{}
+}
+
+// marks resume best/worst de cada alimento na ordem da linha: "b", "w" ou "-".
+func marks(r model.NutrientComparison) string {
+	out := ""
+	for _, v := range r.Values {
+		switch {
+		case v.Best:
+			out += "b"
+		case v.Worst:
+			out += "w"
+		default:
+			out += "-"
+		}
+	}
+	return out
+}
+
+func TestCompareFoodsBestAndWorst(t *testing.T) {
+	measured := func(v float64, unit string) model.NutrientValue {
+		return model.NutrientValue{Value: floatPtr(v), Unit: unit, Status: model.NutrientStatusMeasured}
+	}
+	foods := []model.Food{
+		{Id: "pao-integral", EnergyKcal: 253, ProteinG: 9.4, FiberG: 6.9, Nutrients: map[string]model.NutrientValue{
+			model.NutrientSodium:  measured(506, "mg"),
+			model.NutrientIron:    {Unit: "mg", Status: model.NutrientStatusNotAnalyzed},
+			model.NutrientCalcium: measured(132, "mg"),
+		}},
+		{Id: "pao-frances", EnergyKcal: 300, ProteinG: 8.0, FiberG: 2.3, Nutrients: map[string]model.NutrientValue{
+			model.NutrientSodium:  measured(648, "mg"),
+			model.NutrientIron:    measured(1.0, "mg"),
+			model.NutrientCalcium: measured(16, "mg"),
+		}},
+		{Id: "tapioca", EnergyKcal: 240, ProteinG: 0, FiberG: 2.3, Nutrients: map[string]model.NutrientValue{
+			model.NutrientSodium:  {Unit: "mg", Status: model.NutrientStatusTrace},
+			model.NutrientCalcium: measured(16, "mg"),
+		}},
+	}
+	comparison := CompareFoods(foods, model.CompareBasis100g)
+
+	cases := []struct {
+		key  string
+		want string
+	}{
+		// Mais é melhor.
+		{key: "protein_g", want: "b-w"},
+		// Empate no pior valor: só o primeiro empatado é marcado.
+		{key: "fiber_g", want: "bw-"},
+		// Menos é melhor; traço conta como zero.
+		{key: model.NutrientSodium, want: "-wb"},
+		// Energia é neutra.
+		{key: "energy_kcal", want: "---"},
+		// Só um valor analisado: nada a comparar.
+		{key: model.NutrientIron, want: "---"},
+		{key: model.NutrientCalcium, want: "bw-"},
+	}
+	for _, c := range cases {
+		if got := marks(row(t, comparison, c.key)); got != c.want {
+			t.Errorf("%s: best/worst = %s; esperava %s", c.key, got, c.want)
+		}
+	}
+
+	sodium := row(t, comparison, model.NutrientSodium)
+	if diff := sodium.Values[1].DiffPercent; diff == nil || *diff != 28.1 {
+		t.Errorf("diferença do sódio em relação à referência = %v; esperava 28.1", diff)
+	}
+	if diff := sodium.Values[2].DiffPercent; diff == nil || *diff != -100 {
+		t.Errorf("traço deveria contar como zero na diferença: %v", diff)
+	}
+	if diff := row(t, comparison, model.NutrientIron).Values[1].DiffPercent; diff != nil {
+		t.Errorf("sem valor na referência, a diferença deveria ser nula: %v", *diff)
+	}
+}
+
+func TestCompareFoodsAllEqualHasNoBestOrWorst(t *testing.T) {
+	foods := []model.Food{{Id: "a", ProteinG: 5}, {Id: "b", ProteinG: 5}}
+	if got := marks(row(t, CompareFoods(foods, model.CompareBasis100g), "protein_g")); got != "--" {
+		t.Errorf("valores iguais não deveriam ter best/worst: %s", got)
+	}
+}
+
+func TestCompareFoodsBasisChangesWinner(t *testing.T) {
+	// Por 100 g o primeiro tem mais proteína; por 100 kcal, o segundo.
+	foods := []model.Food{
+		{Id: "queijo", EnergyKcal: 350, ProteinG: 22},
+		{Id: "peito-frango", EnergyKcal: 160, ProteinG: 20},
+	}
+	if got := marks(row(t, CompareFoods(foods, model.CompareBasis100g), "protein_g")); got != "bw" {
+		t.Errorf("por 100 g: %s; esperava bw", got)
+	}
+	if got := marks(row(t, CompareFoods(foods, model.CompareBasisKcal), "protein_g")); got != "wb" {
+		t.Errorf("por 100 kcal: %s; esperava wb", got)
+	}
+}
+
+func TestCompareBasisGrams(t *testing.T) {
+	spoon := model.HouseholdMeasure{Name: "1 colher de sopa", Grams: 25}
+	slice := model.HouseholdMeasure{Name: "1 fatia", Grams: 50, IsDefault: true}
+	cases := []struct {
+		name      string
+		food      model.Food
+		basis     string
+		wantGrams float64
+		wantLabel string
+		wantOK    bool
+	}{
+		{name: "100 g", food: model.Food{EnergyKcal: 200}, basis: model.CompareBasis100g, wantGrams: 100, wantLabel: "100 g", wantOK: true},
+		{name: "kcal", food: model.Food{EnergyKcal: 200}, basis: model.CompareBasisKcal, wantGrams: 50, wantLabel: "100 kcal", wantOK: true},
+		{name: "kcal sem energia", food: model.Food{}, basis: model.CompareBasisKcal},
+		{
+			name:      "medida padrão, mesmo depois de outra",
+			food:      model.Food{HouseholdMeasures: []model.HouseholdMeasure{model.GramMeasure(), spoon, slice}},
+			basis:     model.CompareBasisMeasure,
+			wantGrams: 50, wantLabel: "1 fatia", wantOK: true,
+		},
+		{
+			name:      "padrão é a grama",
+			food:      model.Food{HouseholdMeasures: []model.HouseholdMeasure{model.GramMeasure(), spoon}},
+			basis:     model.CompareBasisMeasure,
+			wantGrams: 100, wantLabel: "100 g (sem medida caseira padrão)", wantOK: true,
+		},
+		{
+			name:      "sem medidas",
+			food:      model.Food{},
+			basis:     model.CompareBasisMeasure,
+			wantGrams: 100, wantLabel: "100 g (sem medida caseira padrão)", wantOK: true,
+		},
+	}
+	for _, c := range cases {
+		grams, label, ok := CompareBasisGrams(c.food, c.basis)
+		if ok != c.wantOK || (ok && (grams != c.wantGrams || label != c.wantLabel)) {
+			t.Errorf("%s: CompareBasisGrams = %g, %q, %t; esperava %g, %q, %t", c.name, grams, label, ok, c.wantGrams, c.wantLabel, c.wantOK)
+		}
+	}
+}
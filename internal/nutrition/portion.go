@@ -0,0 +1,78 @@
+// Package nutrition concentra os cálculos nutricionais feitos sobre model.Food.
+package nutrition
+
+import (
+	"math"
+	"saas-nutri/internal/model"
+)
+
+// PortionRounding descreve o arredondamento aplicado às porções: meio para longe do zero,
+// feito uma única vez sobre o valor final já escalado (nunca em etapas intermediárias).
+var PortionRounding = model.RoundingPolicy{
+	Method: "half_away_from_zero",
+	Stage:  "after_scaling",
+	Rules: []model.RoundingRule{
+		{Fields: []string{"energy_kcal"}, Decimals: 0},
+		{Fields: []string{"grams", "protein_g", "carbohydrate_g", "fat_g", "fiber_g", "nutrients[unit=g]", "nutrients[unit=mg]"}, Decimals: 1},
+		{Fields: []string{"nutrients[unit=mcg]"}, Decimals: 0},
+	},
+}
+
+func Round(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
+
+func unitDecimals(unit string) int {
+	if unit == "mcg" {
+		return 0
+	}
+	return 1
+}
+
+// ScaleNutrients converte valores por 100 g para a quantidade de gramas informada, sem arredondar.
+// Nutrientes em traço ou não analisados mantêm o status e continuam sem valor.
+func ScaleNutrients(nutrients map[string]model.NutrientValue, grams float64) map[string]model.NutrientValue {
+	if len(nutrients) == 0 {
+		return nil
+	}
+	factor := grams / 100
+	scaled := make(map[string]model.NutrientValue, len(nutrients))
+	for key, n := range nutrients {
+		if n.Value != nil {
+			v := *n.Value * factor
+			n.Value = &v
+		}
+		scaled[key] = n
+	}
+	return scaled
+}
+
+// CalculatePortion escala os valores por 100 g do alimento para grams gramas e aplica PortionRounding.
+func CalculatePortion(food model.Food, measure string, quantity, grams float64) model.PortionResponse {
+	factor := grams / 100
+
+	nutrients := ScaleNutrients(food.Nutrients, grams)
+	for key, n := range nutrients {
+		if n.Value != nil {
+			v := Round(*n.Value, unitDecimals(n.Unit))
+			n.Value = &v
+			nutrients[key] = n
+		}
+	}
+
+	return model.PortionResponse{
+		FoodID:        food.Id,
+		FoodName:      food.Name,
+		Measure:       measure,
+		Quantity:      quantity,
+		Grams:         Round(grams, 1),
+		EnergyKcal:    Round(food.EnergyKcal*factor, 0),
+		ProteinG:      Round(food.ProteinG*factor, 1),
+		CarbohydrateG: Round(food.CarbohydrateG*factor, 1),
+		FatG:          Round(food.FatG*factor, 1),
+		FiberG:        Round(food.FiberG*factor, 1),
+		Nutrients:     nutrients,
+		Rounding:      PortionRounding,
+	}
+}
@@ -0,0 +1,106 @@
+package nutrition
+
+import (
+	"reflect"
+	"testing"
+
+	"saas-nutri/internal/model"
+)
+
+func TestRound(t *testing.T) {
+	cases := []struct {
+		value    float64
+		decimals int
+		want     float64
+	}{
+		// Meio para longe do zero, nos dois sentidos.
+		{value: 2.5, decimals: 0, want: 3},
+		{value: -2.5, decimals: 0, want: -3},
+		{value: 0.5, decimals: 0, want: 1},
+		{value: 0.49, decimals: 0, want: 0},
+		{value: 0.05, decimals: 1, want: 0.1},
+		{value: -0.05, decimals: 1, want: -0.1},
+		{value: 0.25, decimals: 1, want: 0.3},
+		{value: 1.25, decimals: 1, want: 1.3},
+		{value: 0.04, decimals: 1, want: 0},
+		// O arredondamento pode subir a casa inteira.
+		{value: 99.95, decimals: 1, want: 100},
+		{value: 1234.5, decimals: 0, want: 1235},
+		{value: 0, decimals: 1, want: 0},
+	}
+	for _, c := range cases {
+		if got := Round(c.value, c.decimals); got != c.want {
+			t.Errorf("Round(%g, %d) = %g; esperava %g", c.value, c.decimals, got, c.want)
+		}
+	}
+}
+
+func TestScaleNutrientsKeepsTraceAndNotAnalyzed(t *testing.T) {
+	nutrients := map[string]model.NutrientValue{
+		model.NutrientSodium:  {Value: floatPtr(10), Unit: "mg", Status: model.NutrientStatusMeasured},
+		model.NutrientIron:    {Unit: "mg", Status: model.NutrientStatusTrace},
+		model.NutrientCalcium: {Unit: "mg", Status: model.NutrientStatusNotAnalyzed},
+	}
+	scaled := ScaleNutrients(nutrients, 25)
+
+	if v := scaled[model.NutrientSodium].Value; v == nil || *v != 2.5 {
+		t.Errorf("sódio escalado = %v; esperava 2.5", v)
+	}
+	for key, status := range map[string]string{model.NutrientIron: model.NutrientStatusTrace, model.NutrientCalcium: model.NutrientStatusNotAnalyzed} {
+		if n := scaled[key]; n.Value != nil || n.Status != status || n.Unit != "mg" {
+			t.Errorf("%s = %+v; deveria manter o status %s sem valor", key, n, status)
+		}
+	}
+	if *nutrients[model.NutrientSodium].Value != 10 {
+		t.Errorf("ScaleNutrients não deveria alterar o mapa de origem")
+	}
+	if ScaleNutrients(nil, 50) != nil {
+		t.Errorf("sem nutrientes deveria devolver nil")
+	}
+}
+
+func TestCalculatePortionRounding(t *testing.T) {
+	food := model.Food{
+		Id: "taco-3", Name: "Arroz, tipo 1, cozido",
+		EnergyKcal: 128, ProteinG: 2.6, CarbohydrateG: 28.1, FatG: 0.2, FiberG: 1.6,
+		Nutrients: map[string]model.NutrientValue{
+			model.NutrientSodium:   {Value: floatPtr(1.3), Unit: "mg", Status: model.NutrientStatusMeasured},
+			model.NutrientVitaminA: {Value: floatPtr(2.2), Unit: "mcg", Status: model.NutrientStatusMeasured},
+			model.NutrientIron:     {Unit: "mg", Status: model.NutrientStatusTrace},
+		},
+	}
+	// 3 colheres de 25 g: 75 g.
+	got := CalculatePortion(food, "1 colher de sopa cheia", 3, 75)
+
+	checks := []struct {
+		field     string
+		got, want float64
+	}{
+		{"grams", got.Grams, 75},
+		// 96 kcal: energia sem casas decimais.
+		{"energy_kcal", got.EnergyKcal, 96},
+		// 1,95 g arredonda para cima só no valor final.
+		{"protein_g", got.ProteinG, 2},
+		{"carbohydrate_g", got.CarbohydrateG, 21.1},
+		// 0,15 g: meio para longe do zero.
+		{"fat_g", got.FatG, 0.2},
+		{"fiber_g", got.FiberG, 1.2},
+		// mg com uma casa (0,975 → 1,0); mcg sem casas (1,65 → 2).
+		{model.NutrientSodium, *got.Nutrients[model.NutrientSodium].Value, 1},
+		{model.NutrientVitaminA, *got.Nutrients[model.NutrientVitaminA].Value, 2},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %g; esperava %g", c.field, c.got, c.want)
+		}
+	}
+	if iron := got.Nutrients[model.NutrientIron]; iron.Value != nil || iron.Status != model.NutrientStatusTrace {
+		t.Errorf("traço deveria continuar sem valor: %+v", iron)
+	}
+	if got.Measure != "1 colher de sopa cheia" || got.Quantity != 3 || got.FoodID != "taco-3" {
+		t.Errorf("identificação da porção inesperada: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Rounding, PortionRounding) {
+		t.Errorf("a resposta deveria trazer a política de arredondamento aplicada")
+	}
+}
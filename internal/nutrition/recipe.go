@@ -0,0 +1,161 @@
+package nutrition
+
+import (
+	"saas-nutri/internal/model"
+	"sort"
+)
+
+const SourceRecipe = "RECIPE"
+
+// IngredientPortion é um ingrediente já resolvido: o alimento (valores por 100 g) e o
+// peso total usado na receita.
+type IngredientPortion struct {
+	Food     model.Food
+	Measure  string
+	Quantity float64
+	Grams    float64
+}
+
+// ComputeRecipe soma os nutrientes dos ingredientes e distribui pelo rendimento da receita.
+// Devolve a receita como alimento (valores por 100 g da preparação pronta, com duas casas
+// decimais, como uma linha da TACO) e o detalhamento arredondado por 100 g, por porção e
+// por ingrediente.
+//
+// Para micronutrientes, a soma considera apenas os ingredientes com valor medido; o status
+// só é "trace" ou "not_analyzed" quando nenhum ingrediente tem valor medido.
+func ComputeRecipe(recipe model.Recipe, ingredients []IngredientPortion) (model.Food, model.RecipeNutrition) {
+	var rawWeight float64
+	var total model.Food
+	nutrients := make(map[string]model.NutrientValue)
+	breakdown := make([]model.PortionResponse, 0, len(ingredients))
+
+	for _, ing := range ingredients {
+		factor := ing.Grams / 100
+		rawWeight += ing.Grams
+		total.EnergyKcal += ing.Food.EnergyKcal * factor
+		total.ProteinG += ing.Food.ProteinG * factor
+		total.CarbohydrateG += ing.Food.CarbohydrateG * factor
+		total.FatG += ing.Food.FatG * factor
+		total.FiberG += ing.Food.FiberG * factor
+
+		for key, n := range ScaleNutrients(ing.Food.Nutrients, ing.Grams) {
+			nutrients[key] = addNutrient(nutrients[key], n)
+		}
+
+		breakdown = append(breakdown, CalculatePortion(ing.Food, ing.Measure, ing.Quantity, ing.Grams))
+	}
+
+	yield := recipe.YieldGrams
+	if yield <= 0 {
+		yield = rawWeight
+	}
+	servings := recipe.Servings
+	if servings <= 0 {
+		servings = 1
+	}
+	servingG := yield / servings
+
+	cookingFactor := 0.0
+	if rawWeight > 0 {
+		cookingFactor = yield / rawWeight
+	}
+
+	per100 := 0.0
+	if yield > 0 {
+		per100 = 100 / yield
+	}
+	food := model.Food{
+		Id:            recipe.Id,
+		Name:          recipe.Name,
+		Source:        SourceRecipe,
+		FoodGroup:     "preparados",
+		EnergyKcal:    Round(total.EnergyKcal*per100, 2),
+		ProteinG:      Round(total.ProteinG*per100, 2),
+		CarbohydrateG: Round(total.CarbohydrateG*per100, 2),
+		FatG:          Round(total.FatG*per100, 2),
+		FiberG:        Round(total.FiberG*per100, 2),
+		HouseholdMeasures: []model.HouseholdMeasure{
+			model.GramMeasure(),
+			{Name: "1 porção", Grams: servingG},
+			{Name: "Receita inteira", Grams: yield},
+		},
+	}
+	food.Tags = mergeIngredientTags(ingredients)
+	if len(nutrients) > 0 {
+		// ScaleNutrients trabalha com gramas sobre 100; 100*per100 resulta no fator 100/yield.
+		food.Nutrients = ScaleNutrients(nutrients, 100*per100)
+		for key, n := range food.Nutrients {
+			if n.Value != nil {
+				v := Round(*n.Value, 2)
+				n.Value = &v
+				food.Nutrients[key] = n
+			}
+		}
+	}
+
+	result := model.RecipeNutrition{
+		Recipe:        recipe,
+		RawWeightG:    Round(rawWeight, 1),
+		CookingFactor: Round(cookingFactor, 2),
+		ServingG:      Round(servingG, 1),
+		Per100g:       CalculatePortion(food, "100 g", 1, 100),
+		PerServing:    CalculatePortion(food, "1 porção", 1, servingG),
+		Breakdown:     breakdown,
+	}
+	return food, result
+}
+
+// mergeIngredientTags une os alérgenos e mantém só as dietas comuns a todos os ingredientes.
+// Se algum ingrediente não tem tags, a receita também fica sem.
+func mergeIngredientTags(ingredients []IngredientPortion) *model.FoodTags {
+	if len(ingredients) == 0 {
+		return nil
+	}
+	allergens := map[string]bool{}
+	diets := map[string]int{}
+	for _, ing := range ingredients {
+		if ing.Food.Tags == nil {
+			return nil
+		}
+		for _, a := range ing.Food.Tags.Allergens {
+			allergens[a] = true
+		}
+		for _, d := range ing.Food.Tags.Diets {
+			diets[d]++
+		}
+	}
+
+	tags := &model.FoodTags{Allergens: []string{}, Diets: []string{}, Source: model.TagSourceRecipe}
+	for a := range allergens {
+		tags.Allergens = append(tags.Allergens, a)
+	}
+	for d, count := range diets {
+		if count == len(ingredients) {
+			tags.Diets = append(tags.Diets, d)
+		}
+	}
+	sort.Strings(tags.Allergens)
+	sort.Strings(tags.Diets)
+	return tags
+}
+
+func addNutrient(acc, n model.NutrientValue) model.NutrientValue {
+	if acc.Unit == "" {
+		acc.Unit = n.Unit
+	}
+	switch {
+	case n.Value != nil:
+		sum := *n.Value
+		if acc.Value != nil {
+			sum += *acc.Value
+		}
+		acc.Value = &sum
+		acc.Status = model.NutrientStatusMeasured
+	case acc.Status == model.NutrientStatusMeasured:
+	case n.Status == model.NutrientStatusTrace || acc.Status == model.NutrientStatusTrace:
+		acc.Status = model.NutrientStatusTrace
+	default:
+		acc.Status = model.NutrientStatusNotAnalyzed
+	}
+	return acc
+}
@@ -0,0 +1,121 @@
+package nutrition
+
+import (
+	"math"
+	"saas-nutri/internal/model"
+	"sort"
+)
+
+// maxSubstituteRatio descarta substitutos cuja porção equivalente passa de tantas vezes a
+// porção original (ex: trocar 50 g de arroz por 600 g de alface).
+const maxSubstituteRatio = 4.0
+
+// substituteQuantityStep é o passo usado para expressar a porção em medidas caseiras.
+const substituteQuantityStep = 0.5
+
+// macroDistance compara os macronutrientes de duas porções de mesma energia pela energia
+// que cada um fornece (4/4/9 kcal por grama), relativa à energia da porção. A fibra entra
+// em gramas com peso menor, só para desempatar.
+func macroDistance(energy, deltaProtein, deltaCarbohydrate, deltaFat, deltaFiber float64) float64 {
+	p := 4 * deltaProtein
+	c := 4 * deltaCarbohydrate
+	f := 9 * deltaFat
+	return math.Sqrt(p*p+c*c+f*f)/energy + math.Abs(deltaFiber)/100
+}
+
+// RankSubstitutes calcula, para cada candidato, a porção de mesma energia que grams gramas do
+// alimento original e devolve os limit candidatos com perfil de macros mais próximo.
+// Os candidatos chegam sem medidas; Portions é preenchido depois com SuggestPortions.
+func RankSubstitutes(original model.Food, grams float64, candidates []model.Food, limit int) []model.SubstituteSuggestion {
+	energy := original.EnergyKcal * grams / 100
+	if energy <= 0 {
+		return []model.SubstituteSuggestion{}
+	}
+	protein := original.ProteinG * grams / 100
+	carbohydrate := original.CarbohydrateG * grams / 100
+	fat := original.FatG * grams / 100
+	fiber := original.FiberG * grams / 100
+
+	suggestions := make([]model.SubstituteSuggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Id == original.Id || candidate.EnergyKcal <= 0 {
+			continue
+		}
+		equivalentGrams := energy / candidate.EnergyKcal * 100
+		if equivalentGrams > grams*maxSubstituteRatio {
+			continue
+		}
+		factor := equivalentGrams / 100
+		s := model.SubstituteSuggestion{
+			Food:          candidate,
+			Grams:         equivalentGrams,
+			EnergyKcal:    candidate.EnergyKcal * factor,
+			ProteinG:      candidate.ProteinG * factor,
+			CarbohydrateG: candidate.CarbohydrateG * factor,
+			FatG:          candidate.FatG * factor,
+			FiberG:        candidate.FiberG * factor,
+		}
+		s.Distance = macroDistance(energy, s.ProteinG-protein, s.CarbohydrateG-carbohydrate, s.FatG-fat, s.FiberG-fiber)
+		suggestions = append(suggestions, s)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].Food.Id < suggestions[j].Food.Id
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	for i := range suggestions {
+		s := &suggestions[i]
+		s.Grams = Round(s.Grams, 1)
+		s.EnergyKcal = Round(s.EnergyKcal, 0)
+		s.ProteinG = Round(s.ProteinG, 1)
+		s.CarbohydrateG = Round(s.CarbohydrateG, 1)
+		s.FatG = Round(s.FatG, 1)
+		s.FiberG = Round(s.FiberG, 1)
+		s.Distance = Round(s.Distance, 3)
+	}
+	return suggestions
+}
+
+// SuggestPortions expressa grams gramas em cada medida caseira (pelo nome exibido, já
+// traduzido), com a quantidade arredondada
+// para meia unidade. As medidas que melhor representam a porção vêm primeiro; a medida em
+// gramas fica sempre por último, com o valor exato e o nome da medida padrão, se houver.
+func SuggestPortions(grams float64, measures []model.HouseholdMeasure) []model.SubstitutePortion {
+	type ranked struct {
+		portion model.SubstitutePortion
+		err     float64
+	}
+	var candidates []ranked
+	gramName := model.GramMeasure().Name
+	for _, m := range measures {
+		if m.IsDefault {
+			gramName = m.Name
+			continue
+		}
+		if m.Grams <= 0 {
+			continue
+		}
+		quantity := math.Round(grams/m.Grams/substituteQuantityStep) * substituteQuantityStep
+		if quantity <= 0 {
+			continue
+		}
+		portionGrams := quantity * m.Grams
+		candidates = append(candidates, ranked{
+			portion: model.SubstitutePortion{MeasureName: m.Name, Quantity: quantity, Grams: Round(portionGrams, 1)},
+			err:     math.Abs(portionGrams-grams) / grams,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].err < candidates[j].err })
+
+	portions := make([]model.SubstitutePortion, 0, len(candidates)+1)
+	for _, c := range candidates {
+		portions = append(portions, c.portion)
+	}
+	return append(portions, model.SubstitutePortion{MeasureName: gramName, Quantity: Round(grams, 0), Grams: Round(grams, 0)})
+}
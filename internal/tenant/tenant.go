@@ -0,0 +1,15 @@
+// Package tenant carrega a identificação da clínica (tenant) autenticada no contexto da requisição.
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(contextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}